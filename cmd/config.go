@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"indexer/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// usoConfig es la ayuda del subcomando "config"
+const usoConfig = "uso: indexer config print-effective|validate [--config archivo] [--env entorno] [--config-dir directorio]"
+
+// runConfig ejecuta el subcomando "config": print-effective imprime la configuración resuelta, validate la comprueba
+func runConfig(args []string) error {
+	if len(args) == 0 || (args[0] != "print-effective" && args[0] != "validate") {
+		return fmt.Errorf(usoConfig)
+	}
+
+	dir := os.Getenv(config.EnvDir)
+	if dir == "" {
+		dir = config.DefaultDir
+	}
+
+	fs := flag.NewFlagSet("config "+args[0], flag.ExitOnError)
+	file := fs.String("config", "", "Archivo YAML de configuración (en lugar de --config-dir y --env)")
+	env := fs.String("env", os.Getenv(config.EnvName), "Entorno a superponer sobre config.base.yaml (dev, staging, prod)")
+	configDir := fs.String("config-dir", dir, "Directorio con los archivos de configuración")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var cfg config.Config
+	var err error
+	if *file != "" {
+		cfg, err = config.LoadFile(*file)
+	} else {
+		cfg, err = config.Load(*configDir, *env)
+	}
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "validate" {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("configuración inválida:\n%w", err)
+		}
+		fmt.Println("✅ Configuración válida")
+		return nil
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	defer encoder.Close()
+
+	return encoder.Encode(cfg)
+}
+
+// loadConfig carga el archivo indicado con --config, o los del directorio de configuración si está vacío
+func loadConfig(file string) (config.Config, error) {
+	if file != "" {
+		return config.LoadFile(file)
+	}
+	return config.LoadFromEnv()
+}
+
+// configFileArg busca --config antes de parsear los flags, porque sus valores por defecto salen de la configuración
+func configFileArg(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"indexer/internal/api"
+	"indexer/internal/tsgen"
+)
+
+// runGen ejecuta el subcomando "gen": indexer gen types --lang ts [--out archivo] | indexer gen openapi [--out archivo]
+func runGen(args []string) error {
+	if len(args) == 0 || (args[0] != "types" && args[0] != "openapi") {
+		return fmt.Errorf("uso: indexer gen types --lang ts [--out archivo] | indexer gen openapi [--out archivo]")
+	}
+
+	fs := flag.NewFlagSet("gen "+args[0], flag.ExitOnError)
+	lang := fs.String("lang", "ts", "Lenguaje de salida (ts, solo para types)")
+	out := fs.String("out", "", "Archivo de salida (vacío = stdout)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if args[0] == "types" && *lang != "ts" {
+		return fmt.Errorf("lenguaje no soportado %q, opciones: ts", *lang)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("error creando %s: %w", *out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	// Especificación OpenAPI 3 de las rutas del API
+	if args[0] == "openapi" {
+		return api.NewOpenAPIGenerator().Write(w)
+	}
+
+	generator := tsgen.NewGenerator()
+	generator.Add(api.Models()...)
+
+	return generator.Write(w)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"indexer/internal/config"
+	"indexer/internal/indexer"
+)
+
+// runIngestTx ejecuta el subcomando "ingest-tx": indexer ingest-tx --hash <hash>
+func runIngestTx(args []string) error {
+	fs := flag.NewFlagSet("ingest-tx", flag.ExitOnError)
+	hash := fs.String("hash", "", "Hash de la transacción a procesar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *hash == "" {
+		return fmt.Errorf("uso: indexer ingest-tx --hash <hash>")
+	}
+
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// Sin API ni ingesta continua, solo la transacción pedida
+	indexerConfig := newIndexerConfig(cfg)
+	indexerConfig.APIAddr = ""
+
+	idx, err := indexer.New(indexerConfig)
+	if err != nil {
+		return err
+	}
+
+	return idx.IngestTransaction(context.Background(), *hash)
+}
@@ -1,41 +1,149 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"indexer/internal/api"
+	"indexer/internal/config"
 	"indexer/internal/indexer"
-
-	"github.com/stellar/go/network"
+	"indexer/internal/indexer/types"
+	"indexer/internal/integration/captivecore_backend"
+	"indexer/internal/integration/datalake_backend"
+	"indexer/internal/integration/rpc_backend"
+	"indexer/internal/logging"
+	"indexer/internal/metrics"
+	"indexer/internal/service/ingest"
+	"indexer/internal/tracing"
 )
 
 func main() {
-	// Parsear flags
+	// Subcomandos
+	if len(os.Args) > 1 && os.Args[1] == "gen" {
+		if err := runGen(os.Args[2:]); err != nil {
+			log.Fatalf("Error generando tipos: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "ingest-tx" {
+		if err := runIngestTx(os.Args[2:]); err != nil {
+			log.Fatalf("Error ingiriendo transacción: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "resume" {
+		if err := runResume(os.Args[2:]); err != nil {
+			log.Fatalf("Error rebobinando checkpoint: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfig(os.Args[2:]); err != nil {
+			log.Fatalf("Error de configuración: %v", err)
+		}
+		return
+	}
+
+	// "run" es el comando por defecto; "backfill" equivale a --backfill
+	backfillCmd := false
+	if len(os.Args) > 1 && (os.Args[1] == "run" || os.Args[1] == "backfill") {
+		backfillCmd = os.Args[1] == "backfill"
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
+	// Configuración por capas: defaults < config.base.yaml < config.<INDEXER_ENV>.yaml < variables INDEXER_* < flags
+	// Con --config se usa ese archivo en lugar del directorio de configuración
+	configFile := configFileArg(os.Args[1:])
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		log.Fatalf("Error cargando configuración: %v", err)
+	}
+
+	// Parsear flags (los valores por defecto vienen de la configuración cargada)
+	flag.String("config", configFile, "Archivo YAML de configuración (vacío = config.base.yaml y config.<INDEXER_ENV>.yaml)")
+	flag.StringVar(&cfg.Backend, "backend", cfg.Backend, "Fuente de ledgers: rpc | datalake | hybrid (data lake y luego RPC) | captive-core")
+	flag.StringVar(&cfg.RPCEndpoint, "rpc", cfg.RPCEndpoint, "RPC endpoint, o lista separada por comas para conmutar entre ellos si uno falla")
+	flag.Float64Var(&cfg.RPCRateLimit.RequestsPerSecond, "rpc-rate-limit", cfg.RPCRateLimit.RequestsPerSecond, "Máximo de peticiones por segundo a cada endpoint RPC (0 = sin límite)")
+	flag.StringVar(&cfg.DataLake.Type, "datalake-type", cfg.DataLake.Type, "Tipo de almacenamiento del data lake: GCS | S3")
+	flag.StringVar(&cfg.DataLake.Bucket, "datalake-bucket", cfg.DataLake.Bucket, "Bucket/prefijo con los ledgers exportados por Galexie")
+	flag.StringVar(&cfg.DataLake.Region, "datalake-region", cfg.DataLake.Region, "Región del bucket (S3)")
+	flag.StringVar(&cfg.DataLake.Endpoint, "datalake-endpoint", cfg.DataLake.Endpoint, "Endpoint S3 compatible (opcional)")
+	flag.UintVar(&cfg.DataLake.Workers, "datalake-workers", cfg.DataLake.Workers, "Descargas de archivos en paralelo")
+	flag.UintVar(&cfg.DataLake.BufferSize, "datalake-buffer", cfg.DataLake.BufferSize, "Archivos de ledgers leídos por adelantado (al menos --datalake-workers)")
+	flag.UintVar(&cfg.DataLake.Handoff, "datalake-handoff", cfg.DataLake.Handoff, "Backend hybrid: distancia a la punta (ledgers) a la que se pasa a RPC")
+	flag.StringVar(&cfg.CaptiveCore.BinaryPath, "captive-core-binary", cfg.CaptiveCore.BinaryPath, "Ruta al binario stellar-core")
+	flag.StringVar(&cfg.CaptiveCore.ConfigPath, "captive-core-config", cfg.CaptiveCore.ConfigPath, "Archivo TOML de captive core (vacío = generado para la red)")
+	flag.StringVar(&cfg.CaptiveCore.StoragePath, "captive-core-storage", cfg.CaptiveCore.StoragePath, "Directorio de trabajo de captive core")
+	flag.UintVar(&cfg.StartLedger, "start", cfg.StartLedger, "Ledger inicial (0 = continuar desde el checkpoint, o desde la punta de la red si no hay)")
+	flag.StringVar(&cfg.Network, "network", cfg.Network, "Network passphrase")
+	flag.StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "Formato de los logs: text | json")
+	flag.StringVar(&cfg.APIAddr, "api", cfg.APIAddr, "Dirección del API HTTP (vacío = deshabilitado)")
+	flag.DurationVar((*time.Duration)(&cfg.SLO.Target), "slo-target", time.Duration(cfg.SLO.Target), "Latencia máxima cierre→indexado del SLO de frescura")
+	flag.Float64Var(&cfg.SLO.Objective, "slo-objective", cfg.SLO.Objective, "Fracción de ledgers que deben cumplir el SLO")
+	flag.UintVar(&cfg.Prefetch, "prefetch", cfg.Prefetch, "Ledgers descargados por adelantado mientras se procesa (0 = bajo demanda)")
+	flag.DurationVar((*time.Duration)(&cfg.TxTimeout), "tx-timeout", time.Duration(cfg.TxTimeout), "Tiempo máximo por transacción y procesador (0 = sin límite)")
+	flag.StringVar(&cfg.CheckpointDir, "checkpoints", cfg.CheckpointDir, "Directorio de checkpoints")
+	flag.UintVar(&cfg.Checkpoint.IntervalLedgers, "checkpoint-ledgers", cfg.Checkpoint.IntervalLedgers, "Guardar el checkpoint cada N ledgers (0 = solo al apagar)")
+	flag.DurationVar((*time.Duration)(&cfg.Checkpoint.Interval), "checkpoint-interval", time.Duration(cfg.Checkpoint.Interval), "Guardar el checkpoint al menos con esta frecuencia (0 = sin límite de tiempo)")
+	flag.UintVar(&cfg.Backfill.ChunkSize, "backfill-chunk", cfg.Backfill.ChunkSize, "Ledgers por chunk de backfill")
+	flag.IntVar(&cfg.Backfill.Workers, "backfill-workers", cfg.Backfill.Workers, "Chunks de backfill procesados en paralelo")
+	flag.UintVar(&cfg.Backfill.MaxLiveLag, "max-live-lag", cfg.Backfill.MaxLiveLag, "Retraso del carril en vivo (ledgers) a partir del cual se pausa el backfill")
+	flag.StringVar(&cfg.Webhooks.File, "webhooks-file", cfg.Webhooks.File, "Archivo JSON con suscripciones de webhooks")
+	flag.StringVar(&cfg.Webhooks.DeadLetters, "webhook-dead-letters", cfg.Webhooks.DeadLetters, "Archivo de notificaciones no entregadas")
 	var (
-		rpcEndpoint = flag.String("rpc", "https://soroban-testnet.stellar.org", "RPC endpoint")
-		startLedger = flag.Uint("start", 0, "Ledger inicial (0 = último)")
-		networkPass = flag.String("network", network.TestNetworkPassphrase, "Network passphrase")
+		backfill = flag.Bool("backfill", false, "Procesar un rango acotado y salir: --backfill <start> <end>")
+		live     = flag.Bool("live", false, "Seguir indexando ledgers en vivo durante el backfill (prioridad al vivo)")
 	)
 	flag.Parse()
 
 	// Configurar logger
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 
-	// Obtener ledger actual si start = 0
-	if *startLedger == 0 {
-		// TODO: Implementar obtención del último ledger
-		*startLedger = 213747 // Por ahora hardcodeado
+	// Validar la configuración final, con los flags aplicados
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Configuración inválida:\n%v", err)
+	}
 
+	// Logs estructurados (text o json); la salida del paquete log pasa por el mismo handler
+	if err := logging.Setup(cfg.LogFormat); err != nil {
+		log.Fatalf("Error configurando logs: %v", err)
 	}
 
-	// Crear configuración
-	config := indexer.Config{
-		RPCEndpoint: *rpcEndpoint,
-		StartLedger: uint32(*startLedger),
-		NetworkPass: *networkPass,
+	// Rango de backfill
+	var backfillRange *types.LedgerRange
+	if *backfill || backfillCmd {
+		backfillRange = parseBackfillRange(flag.Args())
 	}
 
+	// Si start = 0, continuar desde el checkpoint en vivo; sin checkpoint el indexador empieza en la punta de la red
+	if cfg.StartLedger == 0 && (backfillRange == nil || *live) {
+		checkpoint, err := indexer.LiveCheckpoint(context.Background(), cfg.CheckpointDir)
+		if err != nil {
+			log.Fatalf("Error leyendo checkpoint: %v", err)
+		}
+
+		if checkpoint > 0 {
+			cfg.StartLedger = uint(checkpoint) + 1
+			log.Printf("Continuando desde el checkpoint: ledger %d", cfg.StartLedger)
+		}
+	}
+
+	// Crear configuración
+	config := newIndexerConfig(cfg)
+	config.Backfill = backfillRange
+	config.Live = *live
+
+	// Trazas de ingesta (no-op si están deshabilitadas)
+	shutdownTracing := tracing.Setup(tracing.Config{
+		Enabled:       cfg.Tracing.Enabled,
+		SampleRatio:   cfg.Tracing.SampleRatio,
+		SlowThreshold: time.Duration(cfg.Tracing.SlowThreshold),
+	})
+
 	// Crear y ejecutar indexador
 	idx, err := indexer.New(config)
 	if err != nil {
@@ -46,5 +154,142 @@ func main() {
 		log.Fatalf("Error ejecutando indexador: %v", err)
 	}
 
+	// Enviar las trazas pendientes
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("⚠️  Error cerrando trazas: %v", err)
+	}
+
 	os.Exit(0)
 }
+
+// newIndexerConfig traduce la configuración resuelta a la del indexador
+func newIndexerConfig(cfg config.Config) indexer.Config {
+	return indexer.Config{
+		LedgerBackend: cfg.Backend,
+		RPCEndpoint:   cfg.RPCEndpoint,
+		RPCRateLimit:  rpc_backend.RateLimit(cfg.RPCRateLimit),
+		DataLake: datalake_backend.ClientConfig{
+			DataStore: datalake_backend.DataStoreConfig{
+				Type:       cfg.DataLake.Type,
+				BucketPath: cfg.DataLake.Bucket,
+				Region:     cfg.DataLake.Region,
+				Endpoint:   cfg.DataLake.Endpoint,
+
+				LedgersPerFile:    uint32(cfg.DataLake.LedgersPerFile),
+				FilesPerPartition: uint32(cfg.DataLake.FilesPerPartition),
+			},
+			Buffer: datalake_backend.BufferConfig{
+				BufferSize: uint32(cfg.DataLake.BufferSize),
+				NumWorkers: uint32(cfg.DataLake.Workers),
+			},
+		},
+		HandoffLedger: uint32(cfg.DataLake.Handoff),
+		CaptiveCore: captivecore_backend.ClientConfig{
+			BinaryPath:         cfg.CaptiveCore.BinaryPath,
+			ConfigPath:         cfg.CaptiveCore.ConfigPath,
+			StoragePath:        cfg.CaptiveCore.StoragePath,
+			HistoryArchiveURLs: cfg.CaptiveCore.HistoryArchives,
+		},
+		StartLedger: uint32(cfg.StartLedger),
+		NetworkPass: cfg.Network,
+		APIAddr:     cfg.APIAddr,
+		FreshnessSLO: metrics.FreshnessSLOConfig{
+			Target:    time.Duration(cfg.SLO.Target),
+			Objective: cfg.SLO.Objective,
+		},
+		APICacheTTL:        apiCacheTTL(cfg.APICache),
+		APIKeys:            cfg.APIKeys,
+		APIRateLimit:       api.RateLimit(cfg.APIRateLimit),
+		APIRouteRates:      apiRouteRates(cfg.APIRouteRates),
+		APICORSOrigins:     cfg.APICORSOrigins,
+		APIPublicUI:        cfg.APIPublicUI,
+		CheckpointDir:      cfg.CheckpointDir,
+		CheckpointEvery:    uint32(cfg.Checkpoint.IntervalLedgers),
+		CheckpointInterval: time.Duration(cfg.Checkpoint.Interval),
+		BackfillChunk:      uint32(cfg.Backfill.ChunkSize),
+		BackfillPool:       cfg.Backfill.Workers,
+		MaxLiveLag:         uint32(cfg.Backfill.MaxLiveLag),
+		HealthMaxLag:       uint32(cfg.Health.MaxLag),
+		HealthMaxAge:       time.Duration(cfg.Health.MaxLedgerAge),
+		TxTimeout:          time.Duration(cfg.TxTimeout),
+		RetryPolicies:      retryPolicies(cfg.Retries),
+		Prefetch:           int(cfg.Prefetch),
+		EgressHosts:        cfg.EgressHosts,
+		ContractSpecs:      cfg.ContractSpecs,
+		Processors:         customProcessors(cfg.Processors),
+		WebhooksFile:       cfg.Webhooks.File,
+		DeadLetters:        cfg.Webhooks.DeadLetters,
+	}
+}
+
+// retryPolicies convierte los reintentos por clase de error; los campos en cero toman el valor por defecto
+func retryPolicies(retries map[string]config.Retry) map[ingest.ErrorClass]ingest.RetryPolicy {
+	policies := make(map[ingest.ErrorClass]ingest.RetryPolicy, len(retries))
+	for class, retry := range retries {
+		policy := ingest.DefaultRetryPolicies[ingest.ErrorClass(class)]
+		if retry.MaxRetries > 0 {
+			policy.MaxRetries = retry.MaxRetries
+		}
+		if retry.BaseDelay > 0 {
+			policy.BaseDelay = time.Duration(retry.BaseDelay)
+		}
+		if retry.MaxDelay > 0 {
+			policy.MaxDelay = time.Duration(retry.MaxDelay)
+		}
+		policies[ingest.ErrorClass(class)] = policy
+	}
+	return policies
+}
+
+// customProcessors convierte la configuración de procesadores personalizados
+func customProcessors(configs []config.Processor) []indexer.CustomProcessor {
+	list := make([]indexer.CustomProcessor, 0, len(configs))
+	for _, c := range configs {
+		list = append(list, indexer.CustomProcessor(c))
+	}
+	return list
+}
+
+// apiCacheTTL convierte el max-age por ruta de la configuración
+func apiCacheTTL(routes map[string]config.Duration) map[string]time.Duration {
+	ttl := make(map[string]time.Duration, len(routes))
+	for route, maxAge := range routes {
+		ttl[route] = time.Duration(maxAge)
+	}
+	return ttl
+}
+
+// apiRouteRates convierte los límites por ruta de la configuración
+func apiRouteRates(routes map[string]config.RateLimit) map[string]api.RateLimit {
+	limits := make(map[string]api.RateLimit, len(routes))
+	for route, limit := range routes {
+		limits[route] = api.RateLimit(limit)
+	}
+	return limits
+}
+
+// parseBackfillRange convierte los argumentos <start> <end> en un rango acotado
+func parseBackfillRange(args []string) *types.LedgerRange {
+	if len(args) != 2 {
+		log.Fatalf("Uso: indexer backfill [flags] <start> <end>")
+	}
+
+	start, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		log.Fatalf("Ledger inicial inválido %q: %v", args[0], err)
+	}
+
+	end, err := strconv.ParseUint(args[1], 10, 32)
+	if err != nil {
+		log.Fatalf("Ledger final inválido %q: %v", args[1], err)
+	}
+
+	if end < start {
+		log.Fatalf("El ledger final (%d) es anterior al inicial (%d)", end, start)
+	}
+
+	endLedger := uint32(end)
+	return &types.LedgerRange{Start: uint32(start), End: &endLedger}
+}
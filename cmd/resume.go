@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+
+	"indexer/internal/indexer"
+)
+
+// runResume ejecuta el subcomando "resume": indexer resume --at-ledger <n> [--config archivo] [--checkpoints directorio]
+// La configuración se resuelve como en "run", así se rebobina el mismo directorio de checkpoints que usa el indexador
+func runResume(args []string) error {
+	configFile := configFileArg(args)
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	atLedger := fs.Uint("at-ledger", 0, "Ledger al que se rebobina el checkpoint en vivo (se borran los datos posteriores)")
+	fs.String("config", configFile, "Archivo YAML de configuración (vacío = config.base.yaml y config.<INDEXER_ENV>.yaml)")
+	fs.StringVar(&cfg.CheckpointDir, "checkpoints", cfg.CheckpointDir, "Directorio de checkpoints")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *atLedger == 0 {
+		return fmt.Errorf("uso: indexer resume --at-ledger <ledger> [--config archivo] [--checkpoints directorio]")
+	}
+
+	result, err := indexer.ResumeAt(context.Background(), cfg.CheckpointDir, uint32(*atLedger))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checkpoint en vivo: %d -> %d\n", result.Previous, result.Ledger)
+	if result.Record != nil {
+		fmt.Printf("Checkpoint más cercano del historial: ledger %d (%s)\n", result.Record.Ledger, result.Record.SavedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	names := make([]string, 0, len(result.Removed))
+	for name := range result.Removed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  %s: %d filas eliminadas\n", name, result.Removed[name])
+	}
+
+	fmt.Printf("La próxima ejecución continúa desde el ledger %d\n", result.Ledger+1)
+	return nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"indexer/internal/service/ingest"
+)
+
+// handleGetBackfill returns the chunk status and progress of a backfill job
+func (s *Server) handleGetBackfill(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	job, ok := s.deps.Backfills.BackfillJob(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "backfill not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewBackfillResponse(job))
+}
+
+// handleListDecodeFailures returns quarantined events, only unresolved ones unless ?all=true
+func (s *Server) handleListDecodeFailures(w http.ResponseWriter, r *http.Request) {
+	unresolvedOnly := r.URL.Query().Get("all") != "true"
+
+	failures, err := s.deps.Quarantine.List(r.Context(), unresolvedOnly)
+	if err != nil {
+		log.Printf("❌ Error listing decode failures: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing decode failures")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DecodeFailuresResponse{DecodeFailures: failures})
+}
+
+// handleRedecodeFailures retries every unresolved decode failure with the current decoders
+func (s *Server) handleRedecodeFailures(w http.ResponseWriter, r *http.Request) {
+	result, err := s.deps.Quarantine.RedecodeAll(r.Context())
+	if err != nil {
+		log.Printf("❌ Error re-decoding quarantined events: %v", err)
+		writeError(w, http.StatusInternalServerError, "error re-decoding quarantined events")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleListFailedTransactions returns the failed transaction queue, only unresolved entries unless ?all=true
+func (s *Server) handleListFailedTransactions(w http.ResponseWriter, r *http.Request) {
+	unresolvedOnly := r.URL.Query().Get("all") != "true"
+
+	failed, err := s.deps.FailedTxs.List(r.Context(), unresolvedOnly)
+	if err != nil {
+		log.Printf("❌ Error listing failed transactions: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing failed transactions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, FailedTransactionsResponse{FailedTransactions: failed})
+}
+
+// handleRetryFailed reprocesses every unresolved failed transaction
+func (s *Server) handleRetryFailed(w http.ResponseWriter, r *http.Request) {
+	result, err := s.deps.FailedTxs.RetryAll(r.Context())
+	if err != nil {
+		log.Printf("❌ Error retrying failed transactions: %v", err)
+		writeError(w, http.StatusInternalServerError, "error retrying failed transactions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleListSessions returns the processing session history, to correlate data gaps with restarts
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.deps.Sessions.List(r.Context())
+	if err != nil {
+		log.Printf("❌ Error listing sessions: %v", err)
+		writeError(w, http.StatusInternalServerError, "error listing sessions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, SessionsResponse{Sessions: sessions})
+}
+
+// handlePause stops live ingestion after the ledger in progress
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	state, err := s.deps.Ingestion.Pause(r.Context())
+	writeControlResult(w, state, err, "pausing ingestion")
+}
+
+// handleResume continues live ingestion after a pause
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	state, err := s.deps.Ingestion.Resume(r.Context())
+	writeControlResult(w, state, err, "resuming ingestion")
+}
+
+// handleSeek moves live ingestion to ?ledger=N and saves it as the new checkpoint
+func (s *Server) handleSeek(w http.ResponseWriter, r *http.Request) {
+	ledger, err := strconv.ParseUint(r.URL.Query().Get("ledger"), 10, 32)
+	if err != nil || ledger == 0 {
+		writeError(w, http.StatusBadRequest, "ledger must be a positive ledger sequence")
+		return
+	}
+
+	state, err := s.deps.Ingestion.Seek(r.Context(), uint32(ledger))
+	writeControlResult(w, state, err, "seeking ingestion")
+}
+
+// writeControlResult sends the loop state, or 409 when live ingestion is not running
+func writeControlResult(w http.ResponseWriter, state ingest.ControlState, err error, action string) {
+	switch {
+	case errors.Is(err, ingest.ErrNotRunning):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ingest.ErrSeekAheadOfTip):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		log.Printf("❌ Error %s: %v", action, err)
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("error %s: %v", action, err))
+	default:
+		writeJSON(w, http.StatusOK, state)
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"indexer/internal/health"
+	"indexer/internal/indexer/processors"
+	"indexer/internal/indexer/types"
+	"indexer/internal/notify"
+	"indexer/internal/service/backfill"
+	"indexer/internal/service/ingest"
+	"indexer/internal/service/quarantine"
+	"indexer/internal/service/session"
+)
+
+// ErrorResponse is the body returned for failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// BackfillResponse describes the progress of a backfill job
+type BackfillResponse struct {
+	backfill.Job
+	CompletedChunks int     `json:"completed_chunks"`
+	TotalChunks     int     `json:"total_chunks"`
+	PercentComplete float64 `json:"percent_complete"`
+}
+
+// NewBackfillResponse builds the API representation of a backfill job
+func NewBackfillResponse(job backfill.Job) BackfillResponse {
+	return BackfillResponse{
+		Job:             job,
+		CompletedChunks: job.CompletedChunks(),
+		TotalChunks:     len(job.Chunks),
+		PercentComplete: job.PercentComplete(),
+	}
+}
+
+// CreateWebhookRequest is the body accepted by POST /webhooks
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+// WebhookResponse describes a webhook subscription (the secret is never returned)
+type WebhookResponse struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Signed     bool      `json:"signed"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewWebhookResponse builds the API representation of a subscription
+func NewWebhookResponse(sub notify.Subscription) WebhookResponse {
+	return WebhookResponse{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: sub.EventTypes,
+		Signed:     sub.Secret != "",
+		CreatedAt:  sub.CreatedAt,
+	}
+}
+
+// EventTypesResponse lists the distinct event types observed
+type EventTypesResponse struct {
+	EventTypes []processors.EventTypeStats `json:"event_types"`
+}
+
+// writeJSON encodes body as JSON with the given status code
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("⚠️  Error encoding API response: %v", err)
+	}
+}
+
+// DecodeFailuresResponse lists quarantined events that could not be decoded
+type DecodeFailuresResponse struct {
+	DecodeFailures []types.DecodeFailure `json:"decode_failures"`
+}
+
+// FailedTransactionsResponse lists transactions in the dead-letter queue
+type FailedTransactionsResponse struct {
+	FailedTransactions []ingest.FailedTransaction `json:"failed_transactions"`
+}
+
+// SessionsResponse lists the processing sessions, oldest first
+type SessionsResponse struct {
+	Sessions []session.Session `json:"sessions"`
+}
+
+// StatusResponse describes the current ingestion mode and progress
+type StatusResponse struct {
+	Mode string `json:"mode"` // live, backfill or live+backfill
+	ingest.Progress
+}
+
+// LedgerStatsResponse is the per-ledger series of a range with its totals, for dashboards
+type LedgerStatsResponse struct {
+	From    uint32              `json:"from"`
+	To      uint32              `json:"to"`
+	Summary LedgerStatsSummary  `json:"summary"`
+	Ledgers []ingest.LedgerInfo `json:"ledgers"`
+}
+
+// LedgerStatsSummary aggregates the ledgers of a LedgerStatsResponse
+type LedgerStatsSummary struct {
+	Ledgers               int     `json:"ledgers"`
+	Transactions          int     `json:"transactions"`
+	SorobanTransactions   int     `json:"soroban_transactions"`
+	ContractEvents        int     `json:"contract_events"`
+	Deployments           int     `json:"deployments"`
+	StorageChanges        int     `json:"storage_changes"`
+	LedgersPerSecond      float64 `json:"ledgers_per_second"`      // Over the processing time span of the range
+	TransactionsPerSecond float64 `json:"transactions_per_second"` // Over the processing time span of the range
+	AvgProcessingMs       float64 `json:"avg_processing_ms"`
+	MaxLag                uint32  `json:"max_lag"`
+}
+
+// NewLedgerStatsResponse builds the series and totals of the ledgers of a range
+func NewLedgerStatsResponse(from, to uint32, ledgers []ingest.LedgerInfo) LedgerStatsResponse {
+	var summary LedgerStatsSummary
+	var processingMs float64
+	for _, info := range ledgers {
+		summary.Ledgers++
+		summary.Transactions += info.Transactions
+		summary.SorobanTransactions += info.SorobanTransactions
+		summary.ContractEvents += info.ContractEvents
+		summary.Deployments += info.Deployments
+		summary.StorageChanges += info.StorageChanges
+		processingMs += info.ProcessingMs
+		summary.MaxLag = max(summary.MaxLag, info.Lag)
+	}
+
+	if n := len(ledgers); n > 0 {
+		summary.AvgProcessingMs = processingMs / float64(n)
+
+		// Processing times follow sequence order unless the range was re-processed after a seek
+		first, last := ledgers[0].ProcessedAt, ledgers[n-1].ProcessedAt
+		if elapsed := last.Sub(first).Seconds(); n > 1 && elapsed > 0 {
+			summary.LedgersPerSecond = float64(n-1) / elapsed
+			summary.TransactionsPerSecond = float64(summary.Transactions-ledgers[0].Transactions) / elapsed
+		}
+	}
+
+	return LedgerStatsResponse{From: from, To: to, Summary: summary, Ledgers: ledgers}
+}
+
+// writeError sends an ErrorResponse with the given status code
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// Models returns the request and response types exposed by the API, used for client type generation
+func Models() []any {
+	return []any{
+		ErrorResponse{},
+		BackfillResponse{},
+		CreateWebhookRequest{},
+		WebhookResponse{},
+		EventTypesResponse{},
+		DecodeFailuresResponse{},
+		quarantine.RedecodeResult{},
+		FailedTransactionsResponse{},
+		ingest.RetryResult{},
+		SessionsResponse{},
+		StatusResponse{},
+		ingest.ControlState{},
+		LedgerStatsResponse{},
+		health.Report{},
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// withCacheHeaders sets Last-Modified from the freshness of the data behind the route and
+// Cache-Control from the route's configured max-age, answering 304 when the client copy is current
+func (s *Server) withCacheHeaders(route string, lastModified func() time.Time, next http.HandlerFunc) http.HandlerFunc {
+	cacheControl := "no-cache"
+	if maxAge := s.opts.CacheMaxAge[route]; maxAge > 0 {
+		cacheControl = fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", cacheControl)
+
+		// HTTP dates have second precision
+		modified := lastModified().UTC().Truncate(time.Second)
+		if !modified.IsZero() {
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+
+			if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(since) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
+
+// startedAt is the Last-Modified of content built into the binary
+var startedAt = time.Now()
+
+// staticContent is the freshness of routes serving content built into the binary
+func staticContent(*Server) time.Time { return startedAt }
+
+// unknownFreshness is used by read routes whose data has no modification time:
+// they get their Cache-Control max-age but no Last-Modified
+func unknownFreshness(*Server) time.Time { return time.Time{} }
+
+// lastLedgerClosedAt is the close time of the newest indexed ledger
+func (s *Server) lastLedgerClosedAt() time.Time {
+	if s.deps.Status == nil {
+		return time.Time{}
+	}
+	return s.deps.Status.Progress().LastLedgerClosedAt
+}
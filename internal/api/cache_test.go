@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheHeaders(t *testing.T) {
+	modified := time.Date(2025, 3, 1, 12, 0, 0, 500, time.UTC)
+	s := &Server{opts: Options{CacheMaxAge: map[string]time.Duration{"/event-types": 10 * time.Second}}}
+
+	tests := []struct {
+		name         string
+		route        string
+		lastModified time.Time
+		since        time.Time
+		status       int
+		cacheControl string
+	}{
+		{"configured max-age", "/event-types", modified, time.Time{}, http.StatusOK, "public, max-age=10"},
+		{"default no-cache", "/webhooks", modified, time.Time{}, http.StatusOK, "no-cache"},
+		{"client copy is current", "/event-types", modified, modified.Truncate(time.Second), http.StatusNotModified, "public, max-age=10"},
+		{"client copy is stale", "/event-types", modified, modified.Add(-time.Minute), http.StatusOK, "public, max-age=10"},
+		{"unknown freshness", "/status", time.Time{}, modified, http.StatusOK, "no-cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := s.withCacheHeaders(tt.route, func() time.Time { return tt.lastModified }, okHandler)
+			r := httptest.NewRequest(http.MethodGet, tt.route, nil)
+			if !tt.since.IsZero() {
+				r.Header.Set("If-Modified-Since", tt.since.Format(http.TimeFormat))
+			}
+			rec := serveRequest(handler, r)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+
+			wantModified := ""
+			if !tt.lastModified.IsZero() {
+				wantModified = tt.lastModified.Format(http.TimeFormat)
+			}
+			if got := rec.Header().Get("Last-Modified"); got != wantModified {
+				t.Errorf("Last-Modified = %q, want %q", got, wantModified)
+			}
+		})
+	}
+}
+
+func TestReadRoutesSendCacheHeaders(t *testing.T) {
+	s := &Server{deps: allDependencies()}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	// A current If-Modified-Since is answered without calling the handler
+	r := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	r.Header.Set("If-Modified-Since", time.Now().UTC().Format(http.TimeFormat))
+	rec := serveRequest(mux, r)
+
+	if rec.Code != http.StatusNotModified || rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("status = %d, Cache-Control = %q, want 304 with no-cache", rec.Code, rec.Header().Get("Cache-Control"))
+	}
+}
@@ -0,0 +1,10 @@
+package api
+
+import "net/http"
+
+// handleListEventTypes returns every contract event type observed with counts and an example payload
+func (s *Server) handleListEventTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, EventTypesResponse{
+		EventTypes: s.deps.EventTypes.EventTypes(),
+	})
+}
@@ -0,0 +1,394 @@
+package api
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"indexer/internal/logging"
+
+	"golang.org/x/time/rate"
+)
+
+// HeaderAPIKey carries the API key of authenticated requests
+const HeaderAPIKey = "X-API-Key"
+
+// publicPaths can be accessed without an API key
+var publicPaths = map[string]bool{
+	"/health":  true,
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// RateLimit is a token bucket: RequestsPerSecond sustained, Burst requests at once
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// maxLimiters bounds the number of per-client limiters kept in memory
+const maxLimiters = 10000
+
+// withAuth rejects requests without a valid API key, except for public paths and, when
+// PublicUI is set, the dashboard files. Authentication is disabled when no keys are configured.
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	if len(s.opts.APIKeys) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicPaths[r.URL.Path] || (s.opts.PublicUI && isUIAsset(r.URL.Path)) || s.validAPIKey(requestAPIKey(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+	})
+}
+
+// isUIAsset reports whether the path is a static dashboard file. They hold no data,
+// so a browser can load the page and then send the key on its API calls.
+func isUIAsset(path string) bool {
+	return path == "/ui" || strings.HasPrefix(path, "/ui/")
+}
+
+// warnOpenAPI logs a warning when no API keys are configured, a loud one when the
+// address is reachable from other hosts
+func warnOpenAPI(addr string, keys []string) {
+	if len(keys) > 0 {
+		return
+	}
+
+	if isLoopbackAddr(addr) {
+		log.Println("⚠️  No API keys configured, the API is open")
+		return
+	}
+	log.Printf("🚨 No API keys configured and the API listens on %s: anyone who can reach it can read the indexed data and manage webhooks and backfills. Set api_keys or bind api_addr to 127.0.0.1", addr)
+}
+
+// isLoopbackAddr reports whether a listen address only accepts local connections.
+// An empty host listens on every interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validAPIKey compares the key against every configured key in constant time
+func (s *Server) validAPIKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	valid := false
+	for _, candidate := range s.opts.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+			valid = true
+		}
+	}
+
+	return valid
+}
+
+// requestAPIKey reads the key from X-API-Key or an "Authorization: Bearer" header
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+
+	return ""
+}
+
+// rateLimiter keeps a token bucket per client and route
+type rateLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// withRateLimit applies the route's rate limit (or the default one) per client. It runs after withAuth,
+// so only validated API keys get a bucket of their own; anything else is limited by IP.
+func (s *Server) withRateLimit(mux *http.ServeMux, next http.Handler) http.Handler {
+	if s.opts.RateLimit.RequestsPerSecond <= 0 && len(s.opts.RouteRateLimits) == 0 {
+		return next
+	}
+
+	limiter := &rateLimiter{limiters: make(map[string]*rate.Limiter)}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routePath(mux, r)
+
+		limit, ok := s.opts.RouteRateLimits[route]
+		if !ok {
+			limit = s.opts.RateLimit
+		}
+
+		if limit.RequestsPerSecond > 0 && !limiter.allow(s.clientID(r)+" "+route, limit) {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes a token from the client's bucket
+func (l *rateLimiter) allow(key string, limit RateLimit) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.limiters[key]
+	if !ok {
+		// Forget idle clients (full buckets) rather than growing without bound,
+		// so active clients keep their state when the map fills up
+		if len(l.limiters) >= maxLimiters {
+			for key, idle := range l.limiters {
+				if idle.Tokens() >= float64(idle.Burst()) {
+					delete(l.limiters, key)
+				}
+			}
+		}
+		if len(l.limiters) >= maxLimiters {
+			l.limiters = make(map[string]*rate.Limiter)
+		}
+
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		bucket = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+		l.limiters[key] = bucket
+	}
+
+	return bucket.Allow()
+}
+
+// routePath returns the path of the mux pattern matching the request (e.g. "/webhooks/{id}")
+func routePath(mux *http.ServeMux, r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// clientID identifies the caller by the fingerprint of a valid API key, falling back to the remote IP.
+// Unknown keys are ignored, otherwise random keys would each get a fresh bucket.
+// It ends up in logs, so it never contains the key itself.
+func (s *Server) clientID(r *http.Request) string {
+	if key := requestAPIKey(r); s.validAPIKey(key) {
+		return "key:" + keyFingerprint(key)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// keyFingerprint returns a short SHA-256 prefix of an API key, enough to tell keys apart in logs
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
+// withCORS lets browsers on the configured origins call the API. "*" allows any origin.
+func (s *Server) withCORS(next http.Handler) http.Handler {
+	if len(s.opts.CORSOrigins) == 0 {
+		return next
+	}
+
+	allowAll := false
+	allowed := make(map[string]bool, len(s.opts.CORSOrigins))
+	for _, origin := range s.opts.CORSOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every response depends on the origin, including those without CORS headers,
+		// so a shared cache never serves one origin's response to another
+		h := w.Header()
+		h.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!allowAll && !allowed[origin]) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Expose-Headers", "Last-Modified, Retry-After, "+HeaderRequestID)
+
+		// Preflight requests are answered here, before authentication
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, If-Modified-Since, "+HeaderAPIKey+", "+HeaderRequestID)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withCompression gzips responses for clients that accept it.
+// /metrics is skipped because promhttp compresses on its own.
+func withCompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.Close()
+
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// gzipResponseWriter compresses the body once the status is known, leaving empty responses untouched
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	if status != http.StatusNoContent && status != http.StatusNotModified && h.Get("Content-Encoding") == "" {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz != nil {
+		return w.gz.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush pushes compressed data to the client, for streaming responses
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close finishes the gzip stream
+func (w *gzipResponseWriter) Close() error {
+	if w.gz == nil {
+		return nil
+	}
+	return w.gz.Close()
+}
+
+// HeaderRequestID carries the correlation ID of a request, taken from the client or generated
+const HeaderRequestID = "X-Request-ID"
+
+// quietPaths are polled by scrapers and probes and are not logged
+var quietPaths = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// withRequestLog tags each request with a request ID, echoed in the response and attached
+// to the logs written while serving it, and logs its method, path, status and latency
+func (s *Server) withRequestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, id)
+
+		ctx := logging.WithRequestID(r.Context(), id)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		if quietPaths[r.URL.Path] {
+			return
+		}
+		slog.LogAttrs(ctx, slog.LevelInfo, "http request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client", s.clientID(r)),
+		)
+	})
+}
+
+// newRequestID returns a random request ID
+func newRequestID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// statusRecorder remembers the status code written to the client
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush forwards to the wrapped writer, for streaming responses
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// okHandler answers every request with a small body
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.WriteString(w, "ok")
+})
+
+func serveRequest(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestCORS(t *testing.T) {
+	s := &Server{opts: Options{CORSOrigins: []string{"https://app.example/"}}}
+	handler := s.withCORS(okHandler)
+
+	tests := []struct {
+		name        string
+		origin      string
+		allowOrigin string
+	}{
+		{"allowed origin", "https://app.example", "https://app.example"},
+		{"other origin", "https://evil.example", ""},
+		{"no origin", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/event-types", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			rec := serveRequest(handler, r)
+
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			// Responses differ by origin, so caches must key on it even without CORS headers
+			if !slices.Contains(rec.Header().Values("Vary"), "Origin") {
+				t.Errorf("Vary = %v, want Origin", rec.Header().Values("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	s := &Server{opts: Options{CORSOrigins: []string{"*"}}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("preflight reached the handler")
+	})
+
+	r := httptest.NewRequest(http.MethodOptions, "/webhooks", nil)
+	r.Header.Set("Origin", "https://any.example")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := serveRequest(s.withCORS(next), r)
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://any.example" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if rec.Header().Get("Access-Control-Allow-Headers") == "" {
+		t.Error("no Access-Control-Allow-Headers")
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	s := &Server{}
+	rec := serveRequest(s.withCORS(okHandler), httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	if vary := rec.Header().Values("Vary"); len(vary) != 0 {
+		t.Errorf("Vary = %v without CORS", vary)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/status", nil)
+	r.Header.Set("Accept-Encoding", "gzip, deflate")
+	rec := serveRequest(withCompression(okHandler), r)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if !slices.Contains(rec.Header().Values("Vary"), "Accept-Encoding") {
+		t.Errorf("Vary = %v, want Accept-Encoding", rec.Header().Values("Vary"))
+	}
+
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil || string(body) != "ok" {
+		t.Errorf("body = %q, %v, want ok", body, err)
+	}
+}
+
+func TestCompressionSkipped(t *testing.T) {
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		accept  string
+		handler http.Handler
+	}{
+		{"client without gzip", "/status", "", okHandler},
+		{"metrics compress themselves", "/metrics", "gzip", okHandler},
+		{"empty response", "/webhooks/1", "gzip", noContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept-Encoding", tt.accept)
+			}
+			rec := serveRequest(withCompression(tt.handler), r)
+
+			if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Content-Encoding = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		publicUI bool
+		path     string
+		header   string
+		value    string
+		want     int
+	}{
+		{"no key", false, "/status", "", "", http.StatusUnauthorized},
+		{"wrong key", false, "/status", HeaderAPIKey, "nope", http.StatusUnauthorized},
+		{"X-API-Key", false, "/status", HeaderAPIKey, "secret", http.StatusOK},
+		{"bearer token", false, "/status", "Authorization", "Bearer secret", http.StatusOK},
+		{"public path", false, "/healthz", "", "", http.StatusOK},
+		{"dashboard is protected by default", false, "/ui/", "", "", http.StatusUnauthorized},
+		{"dashboard opted in", true, "/ui/app.js", "", "", http.StatusOK},
+		{"opt-in covers only the dashboard", true, "/uix", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{opts: Options{APIKeys: []string{"other", "secret"}, PublicUI: tt.publicUI}}
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+
+			if got := serveRequest(s.withAuth(okHandler), r).Code; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthDisabledWithoutKeys(t *testing.T) {
+	s := &Server{}
+	rec := serveRequest(s.withAuth(okHandler), httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:8080", true},
+		{"localhost:8080", true},
+		{"[::1]:8080", true},
+		{":8080", false},
+		{"0.0.0.0:8080", false},
+		{"10.0.0.5:8080", false},
+		{"indexer.internal:8080", false},
+		{"8080", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopbackAddr(tt.addr); got != tt.want {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /status", okHandler)
+	mux.Handle("POST /webhooks", okHandler)
+
+	s := &Server{opts: Options{
+		APIKeys:         []string{"secret"},
+		RateLimit:       RateLimit{RequestsPerSecond: 0.001, Burst: 2},
+		RouteRateLimits: map[string]RateLimit{"/webhooks": {RequestsPerSecond: 0.001, Burst: 1}},
+	}}
+	handler := s.withRateLimit(mux, mux)
+
+	send := func(method, path, remoteAddr, key string) int {
+		r := httptest.NewRequest(method, path, nil)
+		r.RemoteAddr = remoteAddr
+		if key != "" {
+			r.Header.Set(HeaderAPIKey, key)
+		}
+		return serveRequest(handler, r).Code
+	}
+
+	// The default burst of 2 applies to /status
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := send(http.MethodGet, "/status", "10.0.0.1:1234", ""); got != want {
+			t.Errorf("request %d: status = %d, want %d", i+1, got, want)
+		}
+	}
+
+	// Each route and client has its own bucket
+	if got := send(http.MethodPost, "/webhooks", "10.0.0.1:1234", ""); got != http.StatusOK {
+		t.Errorf("first webhook request: status = %d, want 200", got)
+	}
+	if got := send(http.MethodPost, "/webhooks", "10.0.0.1:1234", ""); got != http.StatusTooManyRequests {
+		t.Errorf("route override: status = %d, want 429", got)
+	}
+	if got := send(http.MethodGet, "/status", "10.0.0.2:1234", ""); got != http.StatusOK {
+		t.Errorf("other client: status = %d, want 200", got)
+	}
+
+	// A valid key gets its own bucket, an unknown key shares the IP's
+	if got := send(http.MethodGet, "/status", "10.0.0.1:1234", "secret"); got != http.StatusOK {
+		t.Errorf("valid key: status = %d, want 200", got)
+	}
+	if got := send(http.MethodGet, "/status", "10.0.0.1:1234", "random"); got != http.StatusTooManyRequests {
+		t.Errorf("unknown key: status = %d, want 429", got)
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /status", okHandler)
+
+	s := &Server{opts: Options{RateLimit: RateLimit{RequestsPerSecond: 0.001}}}
+	handler := s.withRateLimit(mux, mux)
+
+	serveRequest(handler, httptest.NewRequest(http.MethodGet, "/status", nil))
+	rec := serveRequest(handler, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("status = %d, Retry-After = %q, want 429 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
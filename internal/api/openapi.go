@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"indexer/internal/openapi"
+)
+
+// Operations documents every route of the route table, including ones disabled by configuration
+func Operations() []openapi.Operation {
+	routes := apiRoutes()
+	operations := make([]openapi.Operation, 0, len(routes))
+	for _, route := range routes {
+		operations = append(operations, route.Operation)
+	}
+	return operations
+}
+
+// NewOpenAPIGenerator returns a generator loaded with every documented route
+func NewOpenAPIGenerator() *openapi.Generator {
+	generator := openapi.NewGenerator(openapi.Info{
+		Title:         "Indexer API",
+		Version:       "1.0.0",
+		Description:   "Admin and query endpoints of the Stellar indexer",
+		APIKeyHeader:  HeaderAPIKey,
+		ErrorResponse: ErrorResponse{},
+	})
+	generator.Add(Operations()...)
+	return generator
+}
+
+// handleOpenAPI serves the OpenAPI document
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, NewOpenAPIGenerator().Document())
+}
+
+// swaggerUI renders /openapi.json with Swagger UI. The page is served by the indexer but the
+// browser loads the Swagger UI script and stylesheet from unpkg.com, so /docs needs internet
+// access on the client side; /openapi.json itself has no external dependency.
+const swaggerUI = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Indexer API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+// handleDocs serves the Swagger UI page
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(swaggerUI))
+}
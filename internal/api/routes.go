@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"indexer/internal/health"
+	"indexer/internal/metrics"
+	"indexer/internal/openapi"
+	"indexer/internal/service/ingest"
+	"indexer/internal/service/quarantine"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// route is one endpoint of the API: its documentation, its handler and the dependency it needs
+type route struct {
+	openapi.Operation
+	handler func(s *Server) http.Handler
+	enabled func(deps Dependencies) bool // nil = always served
+
+	// lastModified is the freshness of the data behind a read route, which gets cache
+	// headers when set (nil = not cached, zero time = Cache-Control only)
+	lastModified func(s *Server) time.Time
+}
+
+// showAll is the ?all=true parameter of the dead-letter listings
+var showAll = openapi.Param{Name: "all", Type: "boolean", Description: "Include resolved entries"}
+
+// apiRoutes is the route table. Both registerRoutes and the OpenAPI document are built from it,
+// so a route can't be served without being documented.
+func apiRoutes() []route {
+	return []route{
+		{
+			Operation: openapi.Operation{Method: "GET", Path: "/metrics", Tag: "status", Summary: "Prometheus metrics in the text exposition format", Public: true},
+			handler: func(s *Server) http.Handler {
+				return promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+			},
+		},
+		{
+			Operation: openapi.Operation{Method: "GET", Path: "/healthz", Tag: "status", Summary: "Liveness probe, 200 while the process serves requests", Response: health.Report{}, Public: true},
+			handler:   serve((*Server).handleLiveness),
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/openapi.json", Tag: "docs", Summary: "This OpenAPI document"},
+			handler:      serve((*Server).handleOpenAPI),
+			lastModified: staticContent,
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/docs", Tag: "docs", Summary: "Swagger UI page rendering /openapi.json, loaded from the unpkg.com CDN by the browser"},
+			handler:      serve((*Server).handleDocs),
+			lastModified: staticContent,
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/ui/", Tag: "docs", Summary: "Operator dashboard, static files, served without a key when api_public_ui is set"},
+			handler:      func(s *Server) http.Handler { return uiHandler() },
+			lastModified: staticContent,
+		},
+		{
+			Operation: openapi.Operation{Method: "GET", Path: "/health", Tag: "status", Summary: "Dependency checks, 503 when degraded", Response: health.Report{}, Public: true},
+			handler:   serve((*Server).handleHealth),
+			enabled:   func(deps Dependencies) bool { return deps.Health != nil },
+		},
+		{
+			Operation: openapi.Operation{Method: "GET", Path: "/readyz", Tag: "status", Summary: "Readiness probe, 503 until the backend is prepared and ingestion has caught up", Response: health.Report{}, Public: true},
+			handler:   serve((*Server).handleReadiness),
+			enabled:   func(deps Dependencies) bool { return deps.Readiness != nil },
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/status", Tag: "status", Summary: "Ingestion mode, position, lag and rate", Response: StatusResponse{}},
+			handler:      serve((*Server).handleStatus),
+			enabled:      func(deps Dependencies) bool { return deps.Status != nil },
+			lastModified: unknownFreshness, // The tip and pause state change without a new ledger
+		},
+		{
+			Operation: openapi.Operation{Method: "GET", Path: "/stream/progress", Tag: "status", Summary: "Server-Sent Events stream of StatusResponse \"progress\" events"},
+			handler:   serve((*Server).handleStreamProgress),
+			enabled:   func(deps Dependencies) bool { return deps.Status != nil },
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/stats/ledgers", Tag: "status", Summary: "Per-ledger counts, processing time and lag of recent ledgers, with throughput totals", Query: []openapi.Param{{Name: "from", Type: "integer", Description: "First ledger (default: the last 100 stored)"}, {Name: "to", Type: "integer", Description: "Last ledger"}}, Response: LedgerStatsResponse{}},
+			handler:      serve((*Server).handleLedgerStats),
+			enabled:      func(deps Dependencies) bool { return deps.LedgerStats != nil },
+			lastModified: (*Server).lastLedgerClosedAt,
+		},
+		{
+			Operation: openapi.Operation{Method: "POST", Path: "/admin/pause", Tag: "admin", Summary: "Pause live ingestion", Response: ingest.ControlState{}},
+			handler:   serve((*Server).handlePause),
+			enabled:   func(deps Dependencies) bool { return deps.Ingestion != nil },
+		},
+		{
+			Operation: openapi.Operation{Method: "POST", Path: "/admin/resume", Tag: "admin", Summary: "Resume live ingestion", Response: ingest.ControlState{}},
+			handler:   serve((*Server).handleResume),
+			enabled:   func(deps Dependencies) bool { return deps.Ingestion != nil },
+		},
+		{
+			Operation: openapi.Operation{Method: "POST", Path: "/admin/seek", Tag: "admin", Summary: "Move live ingestion to a ledger and save it as the checkpoint", Query: []openapi.Param{{Name: "ledger", Type: "integer", Description: "Next ledger to process"}}, Response: ingest.ControlState{}},
+			handler:   serve((*Server).handleSeek),
+			enabled:   func(deps Dependencies) bool { return deps.Ingestion != nil },
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/admin/backfills/{id}", Tag: "admin", Summary: "Progress of a backfill job", Response: BackfillResponse{}},
+			handler:      serve((*Server).handleGetBackfill),
+			enabled:      func(deps Dependencies) bool { return deps.Backfills != nil },
+			lastModified: unknownFreshness,
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/admin/decode-failures", Tag: "admin", Summary: "Quarantined events that could not be decoded", Query: []openapi.Param{showAll}, Response: DecodeFailuresResponse{}},
+			handler:      serve((*Server).handleListDecodeFailures),
+			enabled:      func(deps Dependencies) bool { return deps.Quarantine != nil },
+			lastModified: unknownFreshness,
+		},
+		{
+			Operation: openapi.Operation{Method: "POST", Path: "/admin/decode-failures/redecode", Tag: "admin", Summary: "Re-decode quarantined events", Response: quarantine.RedecodeResult{}},
+			handler:   serve((*Server).handleRedecodeFailures),
+			enabled:   func(deps Dependencies) bool { return deps.Quarantine != nil },
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/admin/failed-transactions", Tag: "admin", Summary: "Transactions processors failed on", Query: []openapi.Param{showAll}, Response: FailedTransactionsResponse{}},
+			handler:      serve((*Server).handleListFailedTransactions),
+			enabled:      func(deps Dependencies) bool { return deps.FailedTxs != nil },
+			lastModified: unknownFreshness,
+		},
+		{
+			Operation: openapi.Operation{Method: "POST", Path: "/admin/retry-failed", Tag: "admin", Summary: "Reprocess the failed transaction queue", Response: ingest.RetryResult{}},
+			handler:   serve((*Server).handleRetryFailed),
+			enabled:   func(deps Dependencies) bool { return deps.FailedTxs != nil },
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/admin/sessions", Tag: "admin", Summary: "Processing session history", Response: SessionsResponse{}},
+			handler:      serve((*Server).handleListSessions),
+			enabled:      func(deps Dependencies) bool { return deps.Sessions != nil },
+			lastModified: unknownFreshness,
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/event-types", Tag: "events", Summary: "Contract event types observed", Response: EventTypesResponse{}},
+			handler:      serve((*Server).handleListEventTypes),
+			enabled:      func(deps Dependencies) bool { return deps.EventTypes != nil },
+			lastModified: func(s *Server) time.Time { return s.deps.EventTypes.LastUpdated() },
+		},
+		{
+			Operation:    openapi.Operation{Method: "GET", Path: "/webhooks", Tag: "webhooks", Summary: "List webhook subscriptions", Response: []WebhookResponse{}},
+			handler:      serve((*Server).handleListWebhooks),
+			enabled:      func(deps Dependencies) bool { return deps.Webhooks != nil },
+			lastModified: unknownFreshness,
+		},
+		{
+			Operation: openapi.Operation{Method: "POST", Path: "/webhooks", Tag: "webhooks", Summary: "Create a webhook subscription", Request: CreateWebhookRequest{}, Response: WebhookResponse{}, Status: http.StatusCreated},
+			handler:   serve((*Server).handleCreateWebhook),
+			enabled:   func(deps Dependencies) bool { return deps.Webhooks != nil },
+		},
+		{
+			Operation: openapi.Operation{Method: "DELETE", Path: "/webhooks/{id}", Tag: "webhooks", Summary: "Delete a webhook subscription", Status: http.StatusNoContent},
+			handler:   serve((*Server).handleDeleteWebhook),
+			enabled:   func(deps Dependencies) bool { return deps.Webhooks != nil },
+		},
+	}
+}
+
+// serve adapts a handler method to the route table
+func serve(handler func(s *Server, w http.ResponseWriter, r *http.Request)) func(s *Server) http.Handler {
+	return func(s *Server) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { handler(s, w, r) })
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"indexer/internal/config"
+)
+
+// allDependencies enables every route; the handlers are never called
+func allDependencies() Dependencies {
+	return Dependencies{
+		Backfills:   struct{ BackfillProvider }{},
+		Webhooks:    struct{ WebhookRegistry }{},
+		EventTypes:  struct{ EventTypeProvider }{},
+		Quarantine:  struct{ DecodeFailureQuarantine }{},
+		FailedTxs:   struct{ FailedTransactionQueue }{},
+		Sessions:    struct{ SessionHistory }{},
+		Status:      struct{ StatusProvider }{},
+		Ingestion:   struct{ IngestionController }{},
+		LedgerStats: struct{ LedgerStatsProvider }{},
+		Health:      struct{ HealthChecker }{},
+		Readiness:   struct{ HealthChecker }{},
+	}
+}
+
+func TestRegisteredRoutesAreDocumented(t *testing.T) {
+	paths := NewOpenAPIGenerator().Document()["paths"].(map[string]map[string]any)
+
+	// registerRoutes panics on an invalid or duplicate pattern
+	s := &Server{deps: allDependencies()}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	for _, route := range apiRoutes() {
+		pattern := route.Method + " " + route.Path
+
+		if _, ok := paths[route.Path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s is registered but missing from the OpenAPI document", pattern)
+		}
+
+		path := strings.NewReplacer("{id}", "1").Replace(route.Path)
+		if _, registered := mux.Handler(httptest.NewRequest(route.Method, path, nil)); registered != pattern {
+			t.Errorf("%s %s is served by %q, want %q", route.Method, path, registered, pattern)
+		}
+	}
+}
+
+func TestDocumentHasNoUnservedRoutes(t *testing.T) {
+	served := make(map[string]bool)
+	for _, route := range apiRoutes() {
+		served[strings.ToLower(route.Method)+" "+route.Path] = true
+	}
+
+	paths := NewOpenAPIGenerator().Document()["paths"].(map[string]map[string]any)
+	for path, methods := range paths {
+		for method := range methods {
+			if !served[method+" "+path] {
+				t.Errorf("%s %s is documented but not in the route table", method, path)
+			}
+		}
+	}
+}
+
+func TestDisabledRoutesAreNotRegistered(t *testing.T) {
+	s := &Server{}
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	for _, route := range apiRoutes() {
+		path := strings.NewReplacer("{id}", "1").Replace(route.Path)
+		_, registered := mux.Handler(httptest.NewRequest(route.Method, path, nil))
+
+		if route.enabled == nil && registered == "" {
+			t.Errorf("%s %s should always be served", route.Method, route.Path)
+		}
+		if route.enabled != nil && registered == route.Method+" "+route.Path {
+			t.Errorf("%s %s is served without its dependency", route.Method, route.Path)
+		}
+	}
+}
+
+func TestCachedRoutesMatchConfig(t *testing.T) {
+	var cached []string
+	for _, route := range apiRoutes() {
+		if route.lastModified != nil {
+			if route.Method != http.MethodGet {
+				t.Errorf("%s %s has cache headers but is not a read route", route.Method, route.Path)
+			}
+			cached = append(cached, route.Path)
+		}
+	}
+	slices.Sort(cached)
+
+	// config.Validate rejects api_cache_max_age entries outside this list
+	if !slices.Equal(cached, config.CachedAPIRoutes) {
+		t.Errorf("cached routes = %v, config.CachedAPIRoutes = %v", cached, config.CachedAPIRoutes)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Server exposes the indexer HTTP endpoints
+type Server struct {
+	httpServer *http.Server
+	deps       Dependencies
+	opts       Options
+
+	// Closed on shutdown so long-lived streams end instead of holding it up
+	shutdown chan struct{}
+}
+
+// NewServer creates a new API server listening on the given address
+func NewServer(addr string, deps Dependencies, opts Options) *Server {
+	s := &Server{deps: deps, opts: opts, shutdown: make(chan struct{})}
+	warnOpenAPI(addr, opts.APIKeys)
+
+	mux := http.NewServeMux()
+	s.registerRoutes(mux)
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.withRequestLog(s.withCORS(withCompression(s.withAuth(s.withRateLimit(mux, mux))))),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.httpServer.RegisterOnShutdown(func() { close(s.shutdown) })
+
+	return s
+}
+
+// registerRoutes wires every endpoint of the route table whose dependency is configured into the mux,
+// with cache headers on the read routes
+func (s *Server) registerRoutes(mux *http.ServeMux) {
+	for _, route := range apiRoutes() {
+		if route.enabled != nil && !route.enabled(s.deps) {
+			continue
+		}
+
+		handler := route.handler(s)
+		if route.lastModified != nil {
+			handler = s.withCacheHeaders(route.Path, func() time.Time { return route.lastModified(s) }, handler.ServeHTTP)
+		}
+		mux.Handle(route.Method+" "+route.Path, handler)
+	}
+}
+
+// Start begins serving requests in the background
+func (s *Server) Start() {
+	go func() {
+		log.Printf("🌐 API server listening on %s", s.httpServer.Addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("❌ API server error: %v", err)
+		}
+	}()
+}
+
+// Stop gracefully shuts down the server
+func (s *Server) Stop(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// Ledger counts of /stats/ledgers: the default when no range is given, and the most ever returned
+const (
+	defaultStatsLedgers = 100
+	maxStatsLedgers     = 10000
+)
+
+// handleLedgerStats returns the per-ledger series of ?from=&to= (both optional), capped to the latest maxStatsLedgers
+func (s *Server) handleLedgerStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, err := parseLedgerParam(query.Get("from"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "from must be a ledger sequence")
+		return
+	}
+	to, err := parseLedgerParam(query.Get("to"), math.MaxUint32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "to must be a ledger sequence")
+		return
+	}
+	if from > to {
+		writeError(w, http.StatusBadRequest, "from is after to")
+		return
+	}
+
+	limit := maxStatsLedgers
+	if !query.Has("from") && !query.Has("to") {
+		limit = defaultStatsLedgers
+	}
+
+	ledgers := s.deps.LedgerStats.Range(from, to)
+	if len(ledgers) > limit {
+		ledgers = ledgers[len(ledgers)-limit:]
+	}
+
+	writeJSON(w, http.StatusOK, NewLedgerStatsResponse(from, to, ledgers))
+}
+
+// parseLedgerParam parses an optional ledger sequence query parameter
+func parseLedgerParam(value string, fallback uint32) (uint32, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	ledger, err := strconv.ParseUint(value, 10, 32)
+	return uint32(ledger), err
+}
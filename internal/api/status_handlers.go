@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"indexer/internal/health"
+)
+
+// progressInterval is how often /stream/progress sends an update
+const progressInterval = 2 * time.Second
+
+// handleStatus returns the ingestion mode, last processed ledger, lag and rate
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+// handleHealth runs the dependency checks, answering 503 with every check's status when degraded
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, s.deps.Health.Run(r.Context()))
+}
+
+// handleLiveness answers as long as the process can serve requests
+func (s *Server) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, health.Report{Status: health.StatusOK, Checks: map[string]health.Result{}})
+}
+
+// handleReadiness answers 503 until the indexer should receive traffic, e.g. while catching up after a cold start
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, s.deps.Readiness.Run(r.Context()))
+}
+
+// writeHealthReport sends the report with 200 when healthy and 503 otherwise
+func writeHealthReport(w http.ResponseWriter, report health.Report) {
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, report)
+}
+
+// handleStreamProgress sends the status as Server-Sent Events until the client disconnects
+func (s *Server) handleStreamProgress(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		data, err := json.Marshal(s.status())
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "event: progress\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		case <-s.shutdown:
+			return
+		}
+	}
+}
+
+// status builds the current StatusResponse
+func (s *Server) status() StatusResponse {
+	return StatusResponse{
+		Mode:     s.deps.Status.Mode(),
+		Progress: s.deps.Status.Progress(),
+	}
+}
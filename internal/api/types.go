@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"indexer/internal/health"
+	"indexer/internal/indexer/processors"
+	"indexer/internal/indexer/types"
+	"indexer/internal/notify"
+	"indexer/internal/service/backfill"
+	"indexer/internal/service/ingest"
+	"indexer/internal/service/quarantine"
+	"indexer/internal/service/session"
+)
+
+// BackfillProvider gives read access to the state of backfill jobs
+type BackfillProvider interface {
+	BackfillJob(id string) (backfill.Job, bool)
+}
+
+// EventTypeProvider lists the contract event types observed during ingestion
+type EventTypeProvider interface {
+	EventTypes() []processors.EventTypeStats
+	LastUpdated() time.Time
+}
+
+// DecodeFailureQuarantine lists undecodable events and retries them after a decoder fix
+type DecodeFailureQuarantine interface {
+	List(ctx context.Context, unresolvedOnly bool) ([]types.DecodeFailure, error)
+	RedecodeAll(ctx context.Context) (quarantine.RedecodeResult, error)
+}
+
+// FailedTransactionQueue lists transactions processors failed on and retries them
+type FailedTransactionQueue interface {
+	List(ctx context.Context, unresolvedOnly bool) ([]ingest.FailedTransaction, error)
+	RetryAll(ctx context.Context) (ingest.RetryResult, error)
+}
+
+// StatusProvider reports the ingestion mode and progress
+type StatusProvider interface {
+	Mode() string
+	Progress() ingest.Progress
+}
+
+// IngestionController pauses, resumes and moves the live ingestion loop
+type IngestionController interface {
+	Pause(ctx context.Context) (ingest.ControlState, error)
+	Resume(ctx context.Context) (ingest.ControlState, error)
+	Seek(ctx context.Context, ledger uint32) (ingest.ControlState, error)
+}
+
+// LedgerStatsProvider returns the per-ledger counts and timings of recently processed ledgers
+type LedgerStatsProvider interface {
+	Range(from, to uint32) []ingest.LedgerInfo
+}
+
+// HealthChecker runs the dependency checks behind /health
+type HealthChecker interface {
+	Run(ctx context.Context) health.Report
+}
+
+// SessionHistory lists the processing sessions of past and current runs
+type SessionHistory interface {
+	List(ctx context.Context) ([]session.Session, error)
+}
+
+// WebhookRegistry manages webhook subscriptions
+type WebhookRegistry interface {
+	Add(sub notify.Subscription) (notify.Subscription, error)
+	Remove(id string) (bool, error)
+	List() []notify.Subscription
+}
+
+// Options holds HTTP behaviour settings of the server
+type Options struct {
+	CacheMaxAge     map[string]time.Duration // Cache-Control max-age per route path (e.g. "/event-types"), unset = no-cache
+	APIKeys         []string                 // Accepted API keys (empty = no authentication)
+	RateLimit       RateLimit                // Default per-client rate limit (zero = unlimited)
+	RouteRateLimits map[string]RateLimit     // Per-route overrides keyed by route path
+	CORSOrigins     []string                 // Origins allowed to call the API from a browser ("*" = any)
+	PublicUI        bool                     // Serve the dashboard files under /ui/ without an API key
+}
+
+// Dependencies holds the services backing the API endpoints (nil disables the related routes)
+type Dependencies struct {
+	Backfills   BackfillProvider
+	Webhooks    WebhookRegistry
+	EventTypes  EventTypeProvider
+	Quarantine  DecodeFailureQuarantine
+	FailedTxs   FailedTransactionQueue
+	Sessions    SessionHistory
+	Status      StatusProvider
+	Ingestion   IngestionController
+	LedgerStats LedgerStatsProvider
+	Health      HealthChecker
+	Readiness   HealthChecker // Checks behind /readyz
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"embed"
+	"net/http"
+)
+
+// uiFiles holds the operator dashboard, served at /ui/ straight from the embedded ui directory
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// uiHandler serves the embedded dashboard assets
+func uiHandler() http.Handler {
+	return http.FileServerFS(uiFiles)
+}
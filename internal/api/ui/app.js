@@ -0,0 +1,98 @@
+// Operator dashboard: polls the indexer API and renders status, sessions and event types.
+const keyInput = document.getElementById("api-key");
+keyInput.value = localStorage.getItem("indexer-api-key") || "";
+keyInput.addEventListener("change", () => {
+  localStorage.setItem("indexer-api-key", keyInput.value);
+  refresh();
+});
+
+async function get(path) {
+  const headers = keyInput.value ? { "X-API-Key": keyInput.value } : {};
+  const res = await fetch(path, { headers });
+  if (!res.ok) {
+    const body = await res.json().catch(() => ({}));
+    throw new Error(`${path}: ${body.error || res.status}`);
+  }
+  return res.json();
+}
+
+function cell(text, className) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  if (className) td.className = className;
+  return td;
+}
+
+function row(tbody, cells) {
+  const tr = document.createElement("tr");
+  cells.forEach((td) => tr.appendChild(td));
+  tbody.appendChild(tr);
+}
+
+async function loadStatus() {
+  const error = document.getElementById("status-error");
+  try {
+    const status = await get("/status");
+    document.querySelectorAll("#status [data-field]").forEach((dd) => {
+      let value = status[dd.dataset.field];
+      if (typeof value === "number" && !Number.isInteger(value)) value = value.toFixed(2);
+      dd.textContent = value === undefined || value === "" ? "-" : value;
+    });
+    error.textContent = "";
+  } catch (err) {
+    error.textContent = err.message;
+  }
+}
+
+async function loadSessions() {
+  const tbody = document.getElementById("sessions");
+  try {
+    const { sessions } = await get("/admin/sessions");
+    tbody.replaceChildren();
+    (sessions || []).slice(-10).reverse().forEach((s) => {
+      const ledgers = s.end_ledger ? `${s.start_ledger} - ${s.end_ledger}` : `${s.start_ledger} -`;
+      const duration = s.duration_seconds ? `${Math.round(s.duration_seconds)}s` : "running";
+      row(tbody, [cell(s.started_at), cell(s.mode), cell(ledgers), cell(duration), cell(s.exit_reason || "")]);
+    });
+  } catch (err) {
+    tbody.replaceChildren();
+    row(tbody, [cell(err.message, "error")]);
+  }
+}
+
+let eventTypes = [];
+
+function renderEventTypes() {
+  const query = document.getElementById("search").value.trim().toLowerCase();
+  const tbody = document.getElementById("event-types");
+  tbody.replaceChildren();
+  eventTypes
+    .filter((t) => !query || t.type.toLowerCase().includes(query) || (t.example.contract_id || "").toLowerCase().includes(query))
+    .forEach((t) => row(tbody, [cell(t.type), cell(t.count), cell(t.example.contract_id, "mono"), cell(t.last_seen_ledger)]));
+}
+
+async function loadEventTypes() {
+  try {
+    eventTypes = (await get("/event-types")).event_types || [];
+    renderEventTypes();
+  } catch (err) {
+    const tbody = document.getElementById("event-types");
+    tbody.replaceChildren();
+    row(tbody, [cell(err.message, "error")]);
+  }
+}
+
+document.getElementById("search").addEventListener("input", renderEventTypes);
+
+function refresh() {
+  loadStatus();
+  loadSessions();
+  loadEventTypes();
+}
+
+refresh();
+setInterval(loadStatus, 5000);
+setInterval(() => {
+  loadSessions();
+  loadEventTypes();
+}, 30000);
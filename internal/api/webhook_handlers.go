@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"indexer/internal/notify"
+)
+
+// handleListWebhooks returns every registered webhook subscription
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs := s.deps.Webhooks.List()
+
+	response := make([]WebhookResponse, 0, len(subs))
+	for _, sub := range subs {
+		response = append(response, NewWebhookResponse(sub))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleCreateWebhook registers a new webhook subscription
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	sub, err := s.deps.Webhooks.Add(notify.Subscription{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, NewWebhookResponse(sub))
+}
+
+// handleDeleteWebhook removes a webhook subscription
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	found, err := s.deps.Webhooks.Remove(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
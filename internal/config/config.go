@@ -0,0 +1,196 @@
+package config
+
+import (
+	"time"
+
+	"indexer/internal/metrics"
+
+	"github.com/stellar/go/network"
+)
+
+// Config is the indexer configuration resolved from config files and environment variables.
+// Command line flags are applied on top of it in main.
+type Config struct {
+	Backend        string               `yaml:"backend" env:"INDEXER_BACKEND"`
+	RPCEndpoint    string               `yaml:"rpc_endpoint" env:"INDEXER_RPC_ENDPOINT"` // Comma separated list to fail over between endpoints
+	RPCRateLimit   RPCRateLimit         `yaml:"rpc_rate_limit"`
+	Network        string               `yaml:"network" env:"INDEXER_NETWORK"`
+	StartLedger    uint                 `yaml:"start_ledger" env:"INDEXER_START_LEDGER"`
+	APIAddr        string               `yaml:"api_addr" env:"INDEXER_API_ADDR"`
+	APICache       map[string]Duration  `yaml:"api_cache_max_age"`               // Cache-Control max-age per route path
+	APIKeys        []string             `yaml:"api_keys" env:"INDEXER_API_KEYS"` // Comma separated in the environment
+	APIRateLimit   RateLimit            `yaml:"api_rate_limit"`
+	APIRouteRates  map[string]RateLimit `yaml:"api_route_rate_limits"`                           // Per route path overrides
+	APICORSOrigins []string             `yaml:"api_cors_origins" env:"INDEXER_API_CORS_ORIGINS"` // Comma separated in the environment
+	APIPublicUI    bool                 `yaml:"api_public_ui" env:"INDEXER_API_PUBLIC_UI"`       // Serve the dashboard files without an API key
+	TxTimeout      Duration             `yaml:"tx_timeout" env:"INDEXER_TX_TIMEOUT"`
+	Prefetch       uint                 `yaml:"prefetch_ledgers" env:"INDEXER_PREFETCH_LEDGERS"`
+	CheckpointDir  string               `yaml:"checkpoint_dir" env:"INDEXER_CHECKPOINT_DIR"`
+	Checkpoint     Checkpoint           `yaml:"checkpoint"`
+	Retries        map[string]Retry     `yaml:"retries"` // Overrides per error class: rate_limited, timeout, connection, other
+	DataLake       DataLake             `yaml:"datalake"`
+	CaptiveCore    CaptiveCore          `yaml:"captive_core"`
+	SLO            SLO                  `yaml:"slo"`
+	Health         Health               `yaml:"health"`
+	Backfill       Backfill             `yaml:"backfill"`
+	Webhooks       Webhooks             `yaml:"webhooks"`
+	Tracing        Tracing              `yaml:"tracing"`
+	ContractSpecs  map[string]string    `yaml:"contract_specs"` // Spec file (WASM or base64 XDR) per contract ID
+	Processors     []Processor          `yaml:"processors"`
+	EgressHosts    []string             `yaml:"egress_allowlist" env:"INDEXER_EGRESS_ALLOWLIST"` // Comma separated in the environment
+	LogFormat      string               `yaml:"log_format" env:"INDEXER_LOG_FORMAT"`             // text or json
+}
+
+// DataLake configures the Galexie data lake ledger source
+type DataLake struct {
+	Type              string `yaml:"type" env:"INDEXER_DATALAKE_TYPE"`
+	Bucket            string `yaml:"bucket" env:"INDEXER_DATALAKE_BUCKET"`
+	Region            string `yaml:"region" env:"INDEXER_DATALAKE_REGION"`
+	Endpoint          string `yaml:"endpoint" env:"INDEXER_DATALAKE_ENDPOINT"`
+	Workers           uint   `yaml:"workers" env:"INDEXER_DATALAKE_WORKERS"`
+	BufferSize        uint   `yaml:"buffer_size" env:"INDEXER_DATALAKE_BUFFER_SIZE"`                 // Ledger files read ahead, at least Workers
+	LedgersPerFile    uint   `yaml:"ledgers_per_file" env:"INDEXER_DATALAKE_LEDGERS_PER_FILE"`       // 0 = from the bucket manifest
+	FilesPerPartition uint   `yaml:"files_per_partition" env:"INDEXER_DATALAKE_FILES_PER_PARTITION"` // 0 = from the bucket manifest
+	Handoff           uint   `yaml:"handoff_distance" env:"INDEXER_DATALAKE_HANDOFF"`
+}
+
+// CaptiveCore configures the captive stellar-core ledger source
+type CaptiveCore struct {
+	BinaryPath      string   `yaml:"binary_path" env:"INDEXER_CAPTIVE_CORE_BINARY"`
+	ConfigPath      string   `yaml:"config_path" env:"INDEXER_CAPTIVE_CORE_CONFIG"`
+	StoragePath     string   `yaml:"storage_path" env:"INDEXER_CAPTIVE_CORE_STORAGE"`
+	HistoryArchives []string `yaml:"history_archives"`
+}
+
+// Checkpoint configures how often live progress is saved, whichever trigger comes first
+type Checkpoint struct {
+	IntervalLedgers uint     `yaml:"interval_ledgers" env:"INDEXER_CHECKPOINT_INTERVAL_LEDGERS"`
+	Interval        Duration `yaml:"interval" env:"INDEXER_CHECKPOINT_INTERVAL"`
+}
+
+// Retry configures how one class of ledger errors is retried
+type Retry struct {
+	MaxRetries int      `yaml:"max_retries"`
+	BaseDelay  Duration `yaml:"base_delay"`
+	MaxDelay   Duration `yaml:"max_delay"`
+}
+
+// Processor configures a custom processor registered through pkg/processor
+type Processor struct {
+	Name      string            `yaml:"name"`
+	Plugin    string            `yaml:"plugin"`    // Go plugin (.so) that registers it, empty if compiled in
+	Contracts []string          `yaml:"contracts"` // Contract IDs or prefixes it handles (empty = all)
+	Options   map[string]string `yaml:"options"`
+}
+
+// Tracing configures OpenTelemetry spans on the ingestion path
+type Tracing struct {
+	Enabled       bool     `yaml:"enabled" env:"INDEXER_TRACING_ENABLED"`
+	SampleRatio   float64  `yaml:"sample_ratio" env:"INDEXER_TRACING_SAMPLE_RATIO"`
+	SlowThreshold Duration `yaml:"slow_threshold" env:"INDEXER_TRACING_SLOW_THRESHOLD"`
+}
+
+// RateLimit is a per-client token bucket
+type RateLimit struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second" env:"INDEXER_API_RATE_LIMIT"`
+	Burst             int     `yaml:"burst" env:"INDEXER_API_RATE_BURST"`
+}
+
+// RPCRateLimit caps the requests sent to each RPC endpoint
+type RPCRateLimit struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second" env:"INDEXER_RPC_RATE_LIMIT"` // 0 = unlimited
+	Burst             int     `yaml:"burst" env:"INDEXER_RPC_RATE_BURST"`
+}
+
+// Health configures when /health reports ingestion as degraded
+type Health struct {
+	MaxLag       uint     `yaml:"max_lag" env:"INDEXER_HEALTH_MAX_LAG"`               // Ledgers behind the network tip
+	MaxLedgerAge Duration `yaml:"max_ledger_age" env:"INDEXER_HEALTH_MAX_LEDGER_AGE"` // Time since the last ledger was processed
+}
+
+// SLO configures the ingestion freshness objective
+type SLO struct {
+	Target    Duration `yaml:"target" env:"INDEXER_SLO_TARGET"`
+	Objective float64  `yaml:"objective" env:"INDEXER_SLO_OBJECTIVE"`
+}
+
+// Backfill configures how bounded ranges are processed
+type Backfill struct {
+	ChunkSize  uint `yaml:"chunk_size" env:"INDEXER_BACKFILL_CHUNK"`
+	Workers    int  `yaml:"workers" env:"INDEXER_BACKFILL_WORKERS"`
+	MaxLiveLag uint `yaml:"max_live_lag" env:"INDEXER_MAX_LIVE_LAG"`
+}
+
+// Webhooks configures webhook subscriptions and dead letters
+type Webhooks struct {
+	File        string `yaml:"file" env:"INDEXER_WEBHOOKS_FILE"`
+	DeadLetters string `yaml:"dead_letters" env:"INDEXER_WEBHOOK_DEAD_LETTERS"`
+}
+
+// Default returns the built-in configuration used when no file or variable overrides a value
+func Default() Config {
+	return Config{
+		Backend:     "rpc",
+		RPCEndpoint: "https://soroban-testnet.stellar.org",
+		Network:     network.TestNetworkPassphrase,
+		APIAddr:     ":8080",
+		LogFormat:   "text",
+		TxTimeout:   Duration(30 * time.Second),
+		Prefetch:    4,
+		RPCRateLimit: RPCRateLimit{
+			Burst: 10,
+		},
+		CheckpointDir: "data/checkpoints",
+		Checkpoint: Checkpoint{
+			IntervalLedgers: 100,
+			Interval:        Duration(30 * time.Second),
+		},
+		CaptiveCore: CaptiveCore{
+			BinaryPath: "stellar-core",
+		},
+		DataLake: DataLake{
+			Type:       "GCS",
+			Workers:    10,
+			BufferSize: 100,
+			Handoff:    1000,
+		},
+		Health: Health{
+			MaxLag:       20,
+			MaxLedgerAge: Duration(time.Minute),
+		},
+		SLO: SLO{
+			Target:    Duration(metrics.DefaultFreshnessSLO.Target),
+			Objective: metrics.DefaultFreshnessSLO.Objective,
+		},
+		Backfill: Backfill{
+			ChunkSize:  10000,
+			Workers:    4,
+			MaxLiveLag: 5,
+		},
+		Tracing: Tracing{
+			SampleRatio:   0.1,
+			SlowThreshold: Duration(2 * time.Second),
+		},
+		Webhooks: Webhooks{
+			DeadLetters: "data/webhook_dead_letters.jsonl",
+		},
+	}
+}
+
+// Duration is a time.Duration written as "30s" in config files
+type Duration time.Duration
+
+// MarshalYAML encodes the duration in its string form
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
+// UnmarshalText parses a duration such as "30s" or "1m30s"
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
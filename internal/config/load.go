@@ -0,0 +1,173 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables selecting the config files to load
+const (
+	EnvName = "INDEXER_ENV"        // Overlay to apply on top of the base file (dev, staging, prod...)
+	EnvDir  = "INDEXER_CONFIG_DIR" // Directory holding the config files
+)
+
+// DefaultDir is where config files are looked up when INDEXER_CONFIG_DIR is not set
+const DefaultDir = "config"
+
+// Load resolves the configuration in layers: built-in defaults, config.base.yaml,
+// config.<env>.yaml and finally INDEXER_* environment variables.
+// The base file is optional, the overlay must exist when env is set.
+func Load(dir, env string) (Config, error) {
+	cfg := Default()
+
+	if err := mergeFile(&cfg, filepath.Join(dir, "config.base.yaml"), false); err != nil {
+		return Config{}, err
+	}
+
+	if env != "" {
+		if err := mergeFile(&cfg, filepath.Join(dir, fmt.Sprintf("config.%s.yaml", env)), true); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// LoadFile resolves the configuration from a single file instead of the config directory:
+// built-in defaults, the file and then INDEXER_* environment variables.
+func LoadFile(path string) (Config, error) {
+	cfg := Default()
+
+	if err := mergeFile(&cfg, path, true); err != nil {
+		return Config{}, err
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// LoadFromEnv loads the configuration selected by INDEXER_CONFIG_DIR and INDEXER_ENV
+func LoadFromEnv() (Config, error) {
+	dir := os.Getenv(EnvDir)
+	if dir == "" {
+		dir = DefaultDir
+	}
+
+	return Load(dir, os.Getenv(EnvName))
+}
+
+// mergeFile overlays the keys present in a YAML file onto cfg
+func mergeFile(cfg *Config, path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("error reading config %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv overrides every field tagged with `env` whose variable is set
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+
+		name, ok := t.Field(i).Tag.Lookup("env")
+		if !ok {
+			if field.Kind() == reflect.Struct {
+				if err := applyEnv(field); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		value, set := os.LookupEnv(name)
+		if !set {
+			continue
+		}
+
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// setField parses value into a config field
+func setField(field reflect.Value, value string) error {
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/stellar/go/network"
+)
+
+// backends are the ledger sources accepted in the backend setting
+var backends = []string{"rpc", "datalake", "hybrid", "captive-core"}
+
+// CachedAPIRoutes are the API read routes that send cache headers and accept a max-age
+// in api_cache_max_age. It mirrors the route table in internal/api.
+var CachedAPIRoutes = []string{
+	"/admin/backfills/{id}",
+	"/admin/decode-failures",
+	"/admin/failed-transactions",
+	"/admin/sessions",
+	"/docs",
+	"/event-types",
+	"/openapi.json",
+	"/stats/ledgers",
+	"/status",
+	"/ui/",
+	"/webhooks",
+}
+
+// retryClasses are the error classes that can be tuned under retries, see ingest.ErrorClass
+var retryClasses = []string{"rate_limited", "timeout", "connection", "other"}
+
+// Validate checks required settings, value ranges and URL formats and returns every problem found
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if !slices.Contains(backends, c.Backend) {
+		fail("backend", "must be one of %s, got %q", strings.Join(backends, ", "), c.Backend)
+	}
+
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		fail("log_format", "must be text or json, got %q", c.LogFormat)
+	}
+
+	if c.Network == "" {
+		fail("network", "is required")
+	}
+
+	if c.RPCEndpoint == "" && (c.Backend == "rpc" || c.Backend == "hybrid") {
+		fail("rpc_endpoint", "is required for the %s backend", c.Backend)
+	}
+	for _, endpoint := range strings.Split(c.RPCEndpoint, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			if err := checkURL(endpoint); err != nil {
+				fail("rpc_endpoint", "%v", err)
+			}
+		}
+	}
+
+	if c.APIAddr != "" {
+		if _, _, err := net.SplitHostPort(c.APIAddr); err != nil {
+			fail("api_addr", "must be host:port: %v", err)
+		}
+	}
+
+	for _, origin := range c.APICORSOrigins {
+		if origin == "*" {
+			continue
+		}
+		if err := checkURL(origin); err != nil {
+			fail("api_cors_origins", "%v", err)
+		}
+	}
+
+	for route := range c.APICache {
+		if !slices.Contains(CachedAPIRoutes, route) {
+			fail("api_cache_max_age", "route %q must be one of %s", route, strings.Join(CachedAPIRoutes, ", "))
+		}
+	}
+
+	for route, limit := range c.APIRouteRates {
+		if !strings.HasPrefix(route, "/") {
+			fail("api_route_rate_limits", "route %q must start with /", route)
+		}
+		if limit.RequestsPerSecond < 0 || limit.Burst < 0 {
+			fail("api_route_rate_limits", "route %q must not be negative", route)
+		}
+	}
+
+	if c.APIRateLimit.RequestsPerSecond < 0 || c.APIRateLimit.Burst < 0 {
+		fail("api_rate_limit", "must not be negative")
+	}
+
+	if c.RPCRateLimit.RequestsPerSecond < 0 || c.RPCRateLimit.Burst < 0 {
+		fail("rpc_rate_limit", "must not be negative")
+	}
+
+	if c.TxTimeout < 0 {
+		fail("tx_timeout", "must not be negative")
+	}
+
+	if c.CheckpointDir == "" {
+		fail("checkpoint_dir", "is required")
+	}
+
+	if c.Checkpoint.Interval < 0 {
+		fail("checkpoint.interval", "must not be negative")
+	}
+
+	if c.Backend == "datalake" || c.Backend == "hybrid" {
+		if c.DataLake.Type != "GCS" && c.DataLake.Type != "S3" {
+			fail("datalake.type", "must be GCS or S3, got %q", c.DataLake.Type)
+		}
+		if c.DataLake.Bucket == "" {
+			fail("datalake.bucket", "is required for the %s backend", c.Backend)
+		}
+		if c.DataLake.Workers == 0 {
+			fail("datalake.workers", "must be at least 1")
+		}
+		if c.DataLake.Workers > c.DataLake.BufferSize {
+			fail("datalake.buffer_size", "must be at least datalake.workers (%d), got %d", c.DataLake.Workers, c.DataLake.BufferSize)
+		}
+	}
+	if c.DataLake.Endpoint != "" {
+		if err := checkURL(c.DataLake.Endpoint); err != nil {
+			fail("datalake.endpoint", "%v", err)
+		}
+	}
+
+	if c.Backend == "captive-core" {
+		if c.CaptiveCore.BinaryPath == "" {
+			fail("captive_core.binary_path", "is required for the captive-core backend")
+		}
+		if c.Network != network.TestNetworkPassphrase && c.Network != network.PublicNetworkPassphrase {
+			if c.CaptiveCore.ConfigPath == "" {
+				fail("captive_core.config_path", "is required for networks other than testnet and pubnet")
+			}
+			if len(c.CaptiveCore.HistoryArchives) == 0 {
+				fail("captive_core.history_archives", "is required for networks other than testnet and pubnet")
+			}
+		}
+	}
+	for _, archive := range c.CaptiveCore.HistoryArchives {
+		if err := checkURL(archive); err != nil {
+			fail("captive_core.history_archives", "%v", err)
+		}
+	}
+
+	if c.SLO.Target <= 0 {
+		fail("slo.target", "must be positive")
+	}
+	if c.SLO.Objective <= 0 || c.SLO.Objective > 1 {
+		fail("slo.objective", "must be in (0, 1], got %g", c.SLO.Objective)
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("tracing.sample_ratio", "must be in [0, 1], got %g", c.Tracing.SampleRatio)
+	}
+
+	if c.Backfill.Workers < 1 {
+		fail("backfill.workers", "must be at least 1")
+	}
+
+	for class, retry := range c.Retries {
+		key := "retries." + class
+		if !slices.Contains(retryClasses, class) {
+			fail(key, "unknown error class, expected one of %s", strings.Join(retryClasses, ", "))
+		}
+		if retry.MaxRetries < 0 || retry.BaseDelay < 0 || retry.MaxDelay < 0 {
+			fail(key, "must not be negative")
+		}
+		if retry.BaseDelay > 0 && retry.MaxDelay > 0 && retry.BaseDelay > retry.MaxDelay {
+			fail(key, "base_delay is larger than max_delay")
+		}
+	}
+
+	seen := make(map[string]bool, len(c.Processors))
+	for i, p := range c.Processors {
+		if p.Name == "" {
+			fail(fmt.Sprintf("processors[%d].name", i), "is required")
+		} else if seen[p.Name] {
+			fail(fmt.Sprintf("processors[%d].name", i), "%q is configured twice", p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	for contractID, path := range c.ContractSpecs {
+		if path == "" {
+			fail("contract_specs."+contractID, "spec file is required")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkURL accepts absolute http and https URLs
+func checkURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("URL %q must use http or https", raw)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("URL %q has no host", raw)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default().Validate() = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string // Key expected in the error, empty when valid
+	}{
+		{"unknown backend", func(c *Config) { c.Backend = "horizon" }, "backend:"},
+		{"unknown log format", func(c *Config) { c.LogFormat = "xml" }, "log_format:"},
+		{"missing network", func(c *Config) { c.Network = "" }, "network:"},
+		{"rpc backend without endpoint", func(c *Config) { c.RPCEndpoint = "" }, "rpc_endpoint:"},
+		{"datalake backend without endpoint", func(c *Config) {
+			c.Backend, c.RPCEndpoint, c.DataLake.Bucket = "datalake", "", "bucket"
+		}, ""},
+		{"relative endpoint in list", func(c *Config) { c.RPCEndpoint = "https://a.example, b.example" }, "rpc_endpoint:"},
+		{"api addr without port", func(c *Config) { c.APIAddr = "localhost" }, "api_addr:"},
+		{"wildcard cors origin", func(c *Config) { c.APICORSOrigins = []string{"*"} }, ""},
+		{"cache route without slash", func(c *Config) { c.APICache = map[string]Duration{"status": Duration(time.Second)} }, "api_cache_max_age:"},
+		{"cached route", func(c *Config) { c.APICache = map[string]Duration{"/stats/ledgers": Duration(time.Second)} }, ""},
+		{"cache route that is not cached", func(c *Config) { c.APICache = map[string]Duration{"/healthz": Duration(time.Second)} }, "api_cache_max_age:"},
+		{"cache route that does not exist", func(c *Config) { c.APICache = map[string]Duration{"/contracts": Duration(time.Second)} }, "api_cache_max_age:"},
+		{"negative rpc rate", func(c *Config) { c.RPCRateLimit.RequestsPerSecond = -1 }, "rpc_rate_limit:"},
+		{"missing checkpoint dir", func(c *Config) { c.CheckpointDir = "" }, "checkpoint_dir:"},
+		{"datalake without bucket", func(c *Config) { c.Backend = "datalake" }, "datalake.bucket:"},
+		{"datalake bad type", func(c *Config) { c.Backend, c.DataLake.Bucket, c.DataLake.Type = "datalake", "bucket", "azure" }, "datalake.type:"},
+		{"datalake workers above buffer", func(c *Config) {
+			c.Backend, c.DataLake.Bucket, c.DataLake.Workers, c.DataLake.BufferSize = "datalake", "bucket", 20, 10
+		}, "datalake.buffer_size:"},
+		{"datalake workers equal to buffer", func(c *Config) {
+			c.Backend, c.DataLake.Bucket, c.DataLake.Workers, c.DataLake.BufferSize = "datalake", "bucket", 10, 10
+		}, ""},
+		{"hybrid without rpc", func(c *Config) { c.Backend, c.DataLake.Bucket, c.RPCEndpoint = "hybrid", "bucket", "" }, "rpc_endpoint:"},
+		{"captive core custom network without archives", func(c *Config) {
+			c.Backend, c.Network, c.CaptiveCore.ConfigPath = "captive-core", "Private Network", "core.cfg"
+		}, "captive_core.history_archives:"},
+		{"slo objective above 1", func(c *Config) { c.SLO.Objective = 1.5 }, "slo.objective:"},
+		{"tracing ratio below 0", func(c *Config) { c.Tracing.SampleRatio = -0.1 }, "tracing.sample_ratio:"},
+		{"no backfill workers", func(c *Config) { c.Backfill.Workers = 0 }, "backfill.workers:"},
+		{"unknown retry class", func(c *Config) { c.Retries = map[string]Retry{"dns": {MaxRetries: 1}} }, "retries.dns:"},
+		{"retry base above max", func(c *Config) {
+			c.Retries = map[string]Retry{"timeout": {BaseDelay: Duration(time.Minute), MaxDelay: Duration(time.Second)}}
+		}, "retries.timeout:"},
+		{"processor without name", func(c *Config) { c.Processors = []Processor{{}} }, "processors[0].name:"},
+		{"processor configured twice", func(c *Config) { c.Processors = []Processor{{Name: "a"}, {Name: "a"}} }, "processors[1].name:"},
+		{"contract spec without file", func(c *Config) { c.ContractSpecs = map[string]string{"CABC": ""} }, "contract_specs.CABC:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.modify(&c)
+			err := c.Validate()
+
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Validate() = %v, want nil", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("Validate() = nil, want an error on %s", tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("Validate() = %v, want an error on %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	c := Default()
+	c.Backend = ""
+	c.Network = ""
+	c.CheckpointDir = ""
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil")
+	}
+	for _, key := range []string{"backend:", "network:", "checkpoint_dir:"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not mention %s", err, key)
+		}
+	}
+}
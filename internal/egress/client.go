@@ -0,0 +1,56 @@
+package egress
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"indexer/internal/metrics"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// NewHTTPClient creates an HTTP client that enforces the policy and audits every outbound destination.
+// All outbound HTTP from the indexer (RPC, webhooks) must use clients built here.
+func NewHTTPClient(policy *Policy, timeout time.Duration) *http.Client {
+	return newHTTPClient(policy, timeout, http.DefaultTransport)
+}
+
+// newHTTPClient wraps base with tracing and the policy audit
+func newHTTPClient(policy *Policy, timeout time.Duration, base http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &auditTransport{
+			policy: policy,
+			base:   otelhttp.NewTransport(base),
+		},
+	}
+}
+
+// auditTransport checks each request against the policy before sending it
+type auditTransport struct {
+	policy *Policy
+	base   http.RoundTripper
+	seen   sync.Map // Destinations already logged
+}
+
+// RoundTrip blocks requests to hosts outside the allowlist and records the destination
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+
+	if err := t.policy.CheckHost(host); err != nil {
+		metrics.OutboundRequests.WithLabelValues(host, "blocked").Inc()
+		log.Printf("🚫 Blocked outbound request: %s %s", req.Method, req.URL.Redacted())
+		return nil, err
+	}
+
+	metrics.OutboundRequests.WithLabelValues(host, "allowed").Inc()
+
+	// Log each destination once, request volume is tracked by the metric
+	if _, logged := t.seen.LoadOrStore(host, struct{}{}); !logged {
+		log.Printf("🌍 Outbound destination: %s (first request: %s %s)", host, req.Method, req.URL.Redacted())
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,78 @@
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrHostNotAllowed is returned when a destination is not in the egress allowlist
+var ErrHostNotAllowed = errors.New("outbound host not in egress allowlist")
+
+// Policy restricts outbound connections to a list of hosts. A nil or empty policy allows every host.
+type Policy struct {
+	hosts    map[string]struct{}
+	suffixes []string
+}
+
+// NewPolicy creates a policy from host names; "*.example.com" allows every subdomain of example.com
+func NewPolicy(hosts []string) *Policy {
+	p := &Policy{hosts: make(map[string]struct{})}
+
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		switch {
+		case host == "":
+		case strings.HasPrefix(host, "*."):
+			p.suffixes = append(p.suffixes, host[1:])
+		default:
+			p.hosts[host] = struct{}{}
+		}
+	}
+
+	return p
+}
+
+// Enabled reports whether the policy restricts anything
+func (p *Policy) Enabled() bool {
+	return p != nil && (len(p.hosts) > 0 || len(p.suffixes) > 0)
+}
+
+// Allows reports whether connections to host are permitted
+func (p *Policy) Allows(host string) bool {
+	if !p.Enabled() {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	if _, ok := p.hosts[host]; ok {
+		return true
+	}
+
+	for _, suffix := range p.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckURL validates that the host of rawURL is allowed, used to reject configuration at startup
+func (p *Policy) CheckURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return fmt.Errorf("invalid URL %q", rawURL)
+	}
+
+	return p.CheckHost(parsed.Hostname())
+}
+
+// CheckHost validates that host is allowed
+func (p *Policy) CheckHost(host string) error {
+	if !p.Allows(host) {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package egress
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolicyAllows(t *testing.T) {
+	policy := NewPolicy([]string{" RPC.example.org ", "*.hooks.example.com", ""})
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"rpc.example.org", true},
+		{"RPC.Example.Org", true},
+		{"api.rpc.example.org", false},
+		{"a.hooks.example.com", true},
+		{"a.b.hooks.example.com", true},
+		{"hooks.example.com", false}, // "*." only covers subdomains
+		{"evilhooks.example.com", false},
+		{"hooks.example.com.evil.net", false},
+		{"other.example.org", false},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Allows(tt.host); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyDisabled(t *testing.T) {
+	for _, policy := range []*Policy{nil, NewPolicy(nil), NewPolicy([]string{" ", ""})} {
+		if policy.Enabled() {
+			t.Errorf("policy %+v is enabled", policy)
+		}
+		if !policy.Allows("anything.example") {
+			t.Errorf("policy %+v blocks a host", policy)
+		}
+	}
+}
+
+func TestPolicyCheckURL(t *testing.T) {
+	policy := NewPolicy([]string{"*.example.com"})
+
+	tests := []struct {
+		url     string
+		wantErr error // Checked with errors.Is when set
+		valid   bool
+	}{
+		{"https://rpc.example.com:443/soroban", nil, true},
+		{"https://rpc.example.net", ErrHostNotAllowed, false},
+		{"not a url", nil, false},
+		{"https://", nil, false},
+	}
+
+	for _, tt := range tests {
+		err := policy.CheckURL(tt.url)
+		if tt.valid != (err == nil) || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+			t.Errorf("CheckURL(%q) = %v", tt.url, err)
+		}
+	}
+}
+
+func TestHTTPClientBlocksOtherHosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := NewHTTPClient(NewPolicy([]string{"*.example.com"}), 0).Get(server.URL)
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("request outside the allowlist: error = %v, want ErrHostNotAllowed", err)
+	}
+
+	resp, err := NewHTTPClient(nil, 0).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request without allowlist: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,83 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned for destinations on loopback, private or link-local networks
+var ErrPrivateAddress = errors.New("outbound host is a loopback, private or link-local address")
+
+// IsPublicIP reports whether ip is routable outside the host and its local networks
+func IsPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
+
+// CheckPublicHost rejects hosts that are, or resolve to, non-public addresses. Hosts that don't
+// resolve are accepted: they can't be reached either, and the dialer checks again on connect.
+func CheckPublicHost(ctx context.Context, host string) error {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !IsPublicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, addr.IP)
+		}
+	}
+
+	return nil
+}
+
+// NewPublicHTTPClient is NewHTTPClient for destinations supplied by users, such as webhooks.
+// Without an allowlist it refuses to connect to non-public addresses, whatever the host name
+// resolves to at the time; with one, the allowlist is the operator's choice of destinations.
+func NewPublicHTTPClient(policy *Policy, timeout time.Duration) *http.Client {
+	if policy.Enabled() {
+		return NewHTTPClient(policy, timeout)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   rejectPrivateAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	return newHTTPClient(policy, timeout, transport)
+}
+
+// rejectPrivateAddress is a net.Dialer Control hook run on the resolved address of each connection
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package egress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.10", true},
+		{"2001:db8::1", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPublicHost(t *testing.T) {
+	for _, host := range []string{"localhost", "api.localhost", "127.0.0.1", "10.0.0.1", "::1"} {
+		if err := CheckPublicHost(context.Background(), host); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("CheckPublicHost(%s) = %v, want ErrPrivateAddress", host, err)
+		}
+	}
+
+	if err := CheckPublicHost(context.Background(), "203.0.113.10"); err != nil {
+		t.Errorf("CheckPublicHost(203.0.113.10) = %v", err)
+	}
+}
+
+func TestPublicHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Without an allowlist the dialer refuses the loopback test server
+	_, err := NewPublicHTTPClient(nil, 0).Get(server.URL)
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("request without allowlist: error = %v, want ErrPrivateAddress", err)
+	}
+
+	// An allowlist is the operator's choice, so a listed loopback host is reachable
+	resp, err := NewPublicHTTPClient(NewPolicy([]string{"127.0.0.1"}), 0).Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with allowlist: %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,96 @@
+// Package health runs dependency checks and aggregates them into a report.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check statuses
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// checkTimeout bounds each check so a hung dependency can't hang the probe
+const checkTimeout = 5 * time.Second
+
+// CheckFunc inspects one dependency, returning a short description of what it observed
+type CheckFunc func(ctx context.Context) (detail string, err error)
+
+// Result is the outcome of one check
+type Result struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report aggregates every check, degraded when any of them fails
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Healthy reports whether every check passed
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK
+}
+
+// Checker holds the registered checks
+type Checker struct {
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+}
+
+// NewChecker creates a checker with no checks
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]CheckFunc)}
+}
+
+// Add registers a check under name
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[name] = check
+}
+
+// Run executes every check concurrently and returns the report
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+
+			result := Result{Status: StatusOK}
+			detail, err := check(ctx)
+			result.Detail = detail
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = StatusDegraded
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
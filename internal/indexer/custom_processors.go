@@ -0,0 +1,78 @@
+package indexer
+
+import (
+	"fmt"
+	"log"
+	"plugin"
+
+	"indexer/internal/indexer/processors"
+	"indexer/internal/service/ingest"
+	"indexer/pkg/processor"
+)
+
+// CustomProcessor configures a processor registered through pkg/processor
+type CustomProcessor struct {
+	Name      string            // Name the processor was registered with
+	Plugin    string            // Go plugin that registers it, empty if compiled in
+	Contracts []string          // Contract IDs or prefixes whose transactions it handles (empty = all)
+	Options   map[string]string // Passed to its factory
+}
+
+// loadCustomProcessors opens the configured plugins, checks that every configured processor is
+// registered and builds the processors of the live lane. Registered processors without a config
+// entry run with default settings.
+func loadCustomProcessors(configs []CustomProcessor) ([]ingest.Processor, error) {
+	contracts := make(map[string][]string, len(configs))
+	for _, config := range configs {
+		contracts[config.Name] = config.Contracts
+		if config.Plugin != "" {
+			// Opening the plugin runs its init, which registers the processor
+			if _, err := plugin.Open(config.Plugin); err != nil {
+				return nil, fmt.Errorf("error loading processor plugin %s: %w", config.Plugin, err)
+			}
+		}
+	}
+
+	for name := range contracts {
+		if _, ok := processor.Lookup(name); !ok {
+			return nil, fmt.Errorf("processor %q is not registered", name)
+		}
+	}
+
+	list, err := newCustomProcessors(configs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, registration := range processor.Registered() {
+		log.Printf("🧩 Custom processor %s loaded (order %d, contracts %v)", registration.Name, registration.Order, contracts[registration.Name])
+	}
+
+	return list, nil
+}
+
+// newCustomProcessors builds a new instance of every registered processor, in order. Each lane gets
+// its own instances, so pkg/processor can promise that an instance is never called concurrently.
+func newCustomProcessors(configs []CustomProcessor) ([]ingest.Processor, error) {
+	settings := make(map[string]processor.Settings, len(configs))
+	for _, config := range configs {
+		settings[config.Name] = processor.Settings{Contracts: config.Contracts, Options: config.Options}
+	}
+
+	var list []ingest.Processor
+	for _, registration := range processor.Registered() {
+		config := settings[registration.Name]
+
+		p, err := registration.Factory(config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating processor %s: %w", registration.Name, err)
+		}
+		if len(config.Contracts) > 0 {
+			p = processors.NewContractFilter(p, config.Contracts)
+		}
+
+		list = append(list, p)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,128 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"indexer/internal/health"
+)
+
+// newHealthChecker registers the checks behind /health for the configured backend and lanes
+func (idx *Indexer) newHealthChecker() *health.Checker {
+	checker := health.NewChecker()
+
+	switch idx.config.LedgerBackend {
+	case "", LedgerBackendRPC, LedgerBackendHybrid:
+		checker.Add("rpc", idx.checkRPC)
+		if len(idx.rpcPool.Status()) > 1 {
+			checker.Add("rpc_endpoints", idx.checkRPCEndpoints)
+		}
+	}
+
+	if idx.config.runsLive() {
+		createdAt := time.Now()
+		checker.Add("ingestion_lag", idx.checkLag)
+		checker.Add("last_ledger_age", func(ctx context.Context) (string, error) {
+			return idx.checkLedgerAge(createdAt)
+		})
+	}
+
+	return checker
+}
+
+// newReadinessChecker registers the checks behind /readyz: the live lane must be running and caught up
+func (idx *Indexer) newReadinessChecker() *health.Checker {
+	checker := health.NewChecker()
+
+	if idx.config.runsLive() {
+		checker.Add("backend", idx.checkIngestionRunning)
+		checker.Add("caught_up", idx.checkCaughtUp)
+	}
+
+	return checker
+}
+
+// checkIngestionRunning fails until the backend is prepared and the live loop is running
+func (idx *Indexer) checkIngestionRunning(ctx context.Context) (string, error) {
+	if !idx.ingestService.Running() {
+		return "", fmt.Errorf("live ingestion is not running")
+	}
+	return "live ingestion running", nil
+}
+
+// checkCaughtUp fails until a ledger has been processed and the lag is below the threshold
+func (idx *Indexer) checkCaughtUp(ctx context.Context) (string, error) {
+	if idx.ingestService.Progress().NetworkLedger == 0 {
+		return "", fmt.Errorf("no ledger processed yet")
+	}
+	return idx.checkLag(ctx)
+}
+
+// checkRPC asks the RPC server whether it is in sync with the network
+func (idx *Indexer) checkRPC(ctx context.Context) (string, error) {
+	rpcHealth, err := idx.clientConfig.GetHealth(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	detail := fmt.Sprintf("latest ledger %d", rpcHealth.LatestLedger)
+	if rpcHealth.Status != "healthy" {
+		return detail, fmt.Errorf("RPC status is %q", rpcHealth.Status)
+	}
+
+	return detail, nil
+}
+
+// checkRPCEndpoints reports how many endpoints of the failover pool are usable, failing when none is
+func (idx *Indexer) checkRPCEndpoints(ctx context.Context) (string, error) {
+	var healthy, down []string
+	for _, status := range idx.rpcPool.Status() {
+		if status.Healthy {
+			healthy = append(healthy, status.Endpoint)
+		} else {
+			down = append(down, status.Endpoint)
+		}
+	}
+
+	detail := fmt.Sprintf("%d/%d endpoints healthy", len(healthy), len(healthy)+len(down))
+	if len(down) > 0 {
+		detail += ", cooling down: " + strings.Join(down, ", ")
+	}
+	if len(healthy) == 0 {
+		return detail, fmt.Errorf("every RPC endpoint is failing")
+	}
+
+	return detail, nil
+}
+
+// checkLag fails when the live lane is too far behind the network tip
+func (idx *Indexer) checkLag(ctx context.Context) (string, error) {
+	progress := idx.ingestService.Progress()
+
+	detail := fmt.Sprintf("%d ledgers behind", progress.Lag)
+	if idx.config.HealthMaxLag > 0 && progress.Lag > idx.config.HealthMaxLag {
+		return detail, fmt.Errorf("lag of %d ledgers exceeds %d", progress.Lag, idx.config.HealthMaxLag)
+	}
+
+	return detail, nil
+}
+
+// checkLedgerAge fails when no ledger has been processed for too long, counting from startup until the first one
+func (idx *Indexer) checkLedgerAge(createdAt time.Time) (string, error) {
+	progress := idx.ingestService.Progress()
+
+	since := createdAt
+	detail := "no ledger processed yet"
+	if !progress.LastProcessedAt.IsZero() {
+		since = progress.LastProcessedAt
+		detail = fmt.Sprintf("ledger %d processed %s ago", progress.LastLedger, time.Since(since).Round(time.Second))
+	}
+
+	if idx.config.HealthMaxAge > 0 && time.Since(since) > idx.config.HealthMaxAge {
+		return detail, fmt.Errorf("no ledger processed in the last %s", idx.config.HealthMaxAge)
+	}
+
+	return detail, nil
+}
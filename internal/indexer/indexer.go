@@ -1,30 +1,150 @@
 package indexer
 
 import (
+	"context"
 	"fmt"
 	"indexer/internal/service/ingest"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
+	"time"
 
+	"indexer/internal/api"
+	"indexer/internal/egress"
 	"indexer/internal/indexer/processors"
+	"indexer/internal/indexer/types"
+	"indexer/internal/integration/captivecore_backend"
+	"indexer/internal/integration/datalake_backend"
 	"indexer/internal/integration/rpc_backend"
+	"indexer/internal/metrics"
+	"indexer/internal/notify"
+	"indexer/internal/service/backfill"
+	"indexer/internal/service/captivecore"
+	"indexer/internal/service/datalake"
+	"indexer/internal/service/hybrid"
+	"indexer/internal/service/quarantine"
 	"indexer/internal/service/rpc"
+	"indexer/internal/service/session"
+	"indexer/internal/storage"
 )
 
+// Supported ledger backends
+const (
+	LedgerBackendRPC      = "rpc"          // Stellar RPC getLedgers
+	LedgerBackendDataLake = "datalake"     // Galexie LedgerCloseMeta files on GCS/S3
+	LedgerBackendHybrid   = "hybrid"       // Data lake for history, RPC once close to the tip
+	LedgerBackendCaptive  = "captive-core" // Local stellar-core subprocess
+)
+
+// drainTimeout bounds how long shutdown waits for buffered events and webhook deliveries
+const drainTimeout = 10 * time.Second
+
+// ledgerStatsCapacity is how many live ledgers /stats/ledgers keeps, one day at ~5s per ledger
+const ledgerStatsCapacity = 17280
+
+// Config holds the settings needed to build an indexer
+type Config struct {
+	LedgerBackend      string                                   // Ledger source, one of the LedgerBackend* constants
+	RPCEndpoint        string                                   // RPC server endpoint URL, or a comma separated list to fail over between
+	RPCRateLimit       rpc_backend.RateLimit                    // Requests per second sent to each RPC endpoint
+	DataLake           datalake_backend.ClientConfig            // Data lake settings when LedgerBackend is "datalake" or "hybrid"
+	HandoffLedger      uint32                                   // Distance to the tip at which the hybrid backend switches to RPC
+	CaptiveCore        captivecore_backend.ClientConfig         // Captive core settings when LedgerBackend is "captive-core"
+	StartLedger        uint32                                   // First ledger to ingest (0 = the network tip)
+	NetworkPass        string                                   // Stellar network passphrase
+	APIAddr            string                                   // Listen address for the HTTP API (empty disables it)
+	FreshnessSLO       metrics.FreshnessSLOConfig               // Ingestion freshness objective
+	CheckpointDir      string                                   // Directory where checkpoints and backfill jobs are stored
+	CheckpointEvery    uint32                                   // Save the live checkpoint every N ledgers (0 = only on shutdown)
+	CheckpointInterval time.Duration                            // Save the live checkpoint at least this often (0 = no time trigger)
+	Backfill           *types.LedgerRange                       // Bounded range to backfill instead of streaming (nil = live mode)
+	BackfillChunk      uint32                                   // Ledgers per backfill chunk (0 = single chunk)
+	BackfillPool       int                                      // Number of backfill chunks processed in parallel
+	Live               bool                                     // Keep streaming live ledgers while a backfill runs
+	MaxLiveLag         uint32                                   // Live lag (in ledgers) above which backfill work pauses
+	HealthMaxLag       uint32                                   // Live lag (in ledgers) above which /health reports degraded
+	HealthMaxAge       time.Duration                            // Time without a processed ledger after which /health reports degraded
+	Prefetch           int                                      // Ledgers fetched ahead of processing
+	TxTimeout          time.Duration                            // Deadline for a processor to handle one transaction (0 = none)
+	RetryPolicies      map[ingest.ErrorClass]ingest.RetryPolicy // Overrides of ingest.DefaultRetryPolicies per error class
+	APICacheTTL        map[string]time.Duration                 // Cache-Control max-age per API route
+	APIKeys            []string                                 // Accepted API keys (empty = open API)
+	APIRateLimit       api.RateLimit                            // Default per-client API rate limit
+	APIRouteRates      map[string]api.RateLimit                 // Per-route API rate limits
+	APICORSOrigins     []string                                 // Browser origins allowed to call the API
+	APIPublicUI        bool                                     // Serve the dashboard files without an API key
+	EgressHosts        []string                                 // Hosts outbound connections may reach (empty = unrestricted)
+	Processors         []CustomProcessor                        // Processors registered through pkg/processor and their settings
+	ContractSpecs      map[string]string                        // Spec file (WASM or base64 XDR) per contract ID, used to decode event payloads
+	WebhooksFile       string                                   // JSON file with webhook subscriptions, loaded at startup and rewritten on API changes
+	DeadLetters        string                                   // File where undeliverable webhook notifications are written
+}
+
 // Indexer is the main coordinator that manages the ledger backend, ingest service, and processors
 type Indexer struct {
-	ingestService *ingest.OrchestratorService
-	processors    []ingest.Processor
+	config              Config
+	clientConfig        rpc_backend.ClientConfig
+	networkTip          ingest.NetworkTipFunc // nil when the backend has no tip source
+	rpcPool             *rpc_backend.Pool
+	ingestService       *ingest.OrchestratorService
+	processors          []ingest.Processor // Processors of the live lane
+	builtinProcessors   []ingest.Processor // Built-in processors, safe for concurrent use and shared by every lane
+	usdcProcessor       *processors.USDCTransferProcessor
+	failedTxs           ingest.FailedTransactionStore
+	backfillCheckpoints ingest.CheckpointStore
+	priorityGate        *ingest.PriorityGate
+	backfill            *backfill.Coordinator
+	dispatcher          *notify.Dispatcher
+	ledgerBackend       rpc.LedgerBackendHandlerService
+	sessions            *session.Tracker
+	apiServer           *api.Server
 }
 
 // New creates a new indexer instance with the given configuration
-func New() (*Indexer, error) {
+func New(config Config) (*Indexer, error) {
+
+	// Transactions are decoded with the network passphrase, a wrong one breaks every hash
+	if config.NetworkPass == "" {
+		return nil, ingest.ErrNoNetworkPassphrase
+	}
+
+	// Every outbound destination must be allowlisted, fail fast on misconfiguration
+	egressPolicy := egress.NewPolicy(config.EgressHosts)
+	if err := checkEgress(config, egressPolicy); err != nil {
+		return nil, err
+	}
+
+	// Contract specs name the fields of example event payloads
+	contractSpecs, err := processors.LoadContractSpecs(config.ContractSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Registered and plugin processors run after the built-in ones
+	customProcessors, err := loadCustomProcessors(config.Processors)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests fail over between the configured RPC endpoints, each one rate limited on its own
+	rpcLimiter := rpc_backend.NewRateLimiter(egress.NewHTTPClient(egressPolicy, 0).Transport, config.RPCRateLimit)
+	rpcPool, err := rpc_backend.NewPool(rpc_backend.SplitEndpoints(config.RPCEndpoint), rpcLimiter)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create RPC client configuration
 	clientConfig := rpc_backend.ClientConfig{
-		BufferSize: 25,
+		Endpoint:          rpcPool.Primary(),
+		HTTPClient:        &http.Client{Transport: rpcPool},
+		NetworkPassphrase: config.NetworkPass,
+		BufferSize:        25,
 		TimeoutConfig: rpc_backend.ClientTimeoutConfig{
 			Timeout:  30,
 			Retries:  3,
@@ -32,39 +152,204 @@ func New() (*Indexer, error) {
 		},
 	}
 
-	// Create ledger backend
-	ledgerBackend := &rpc.LedgerBackend{
-		ClientConfig: clientConfig,
+	// Without a start ledger or checkpoint, the live lane starts at the tip of the configured source
+	tip := networkTip(config, clientConfig)
+	if config.runsLive() && config.StartLedger == 0 && tip == nil {
+		return nil, fmt.Errorf("the %s backend can't report the network tip, set a start ledger with --start", config.LedgerBackend)
 	}
 
-	// Start the backend
-	if err := ledgerBackend.Start(); err != nil {
-		return nil, fmt.Errorf("error starting ledger backend: %w", err)
+	// Create ledger backend
+	ledgerBackend, err := newLedgerBackend(config, clientConfig)
+	if err != nil {
+		return nil, err
 	}
 
+	// Undecodable events are quarantined so they can be re-decoded after a fix
+	decodeFailureStore := storage.NewFileDecodeFailureStore(filepath.Join(config.CheckpointDir, decodeFailuresFile))
+	decodeFailures := quarantine.NewService(decodeFailureStore)
+
 	// Create processors
-	usdcProcessor := processors.NewUSDCTransferProcessor()
-	processorList := []ingest.Processor{usdcProcessor}
+	usdcProcessor := processors.NewUSDCTransferProcessor(decodeFailures)
+	decodeFailures.Register(usdcProcessor)
+	eventTypeProcessor := processors.NewEventTypeProcessor(contractSpecs)
+	builtinProcessors := []ingest.Processor{usdcProcessor, eventTypeProcessor}
+	processorList := append(slices.Clone(builtinProcessors), customProcessors...)
+
+	// Transactions a processor fails on are queued for a later retry
+	failedTxs := storage.NewFileFailedTransactionStore(filepath.Join(config.CheckpointDir, failedTransactionsFile))
+
+	// Each lane keeps its own checkpoint so backfills never touch live progress.
+	// Live checkpoints are kept as a history so ingestion can be rewound to one of them.
+	liveCheckpoints := newLiveCheckpoints(config.CheckpointDir, decodeFailureStore, failedTxs)
+
+	// Per-ledger counts and timings of the live lane, served by /stats/ledgers
+	ledgerStats := storage.NewMemoryLedgerInfoStore(ledgerStatsCapacity)
+
+	// Historical ledgers are excluded from the freshness SLO
+	var freshness *metrics.FreshnessTracker
+	if config.runsLive() {
+		freshness = metrics.NewFreshnessTracker(config.FreshnessSLO)
+	}
+
+	// Live ledgers take priority when both lanes run together
+	var priorityGate *ingest.PriorityGate
+	if config.Backfill != nil && config.Live {
+		priorityGate = ingest.NewPriorityGate(config.MaxLiveLag)
+	}
 
 	// Create ingest service
-	ingestService := ingest.NewIngestService(ledgerBackend, processorList)
+	ingestService := ingest.NewIngestService(ledgerBackend, processorList, ingest.Options{
+		NetworkPassphrase:  config.NetworkPass,
+		CheckpointStore:    liveCheckpoints,
+		CheckpointEvery:    config.CheckpointEvery,
+		CheckpointInterval: config.CheckpointInterval,
+		FailedTransactions: failedTxs,
+		Freshness:          freshness,
+		PriorityGate:       priorityGate,
+		TxTimeout:          config.TxTimeout,
+		RetryPolicies:      config.RetryPolicies,
+		LedgerInfo:         ledgerStats,
+		Prefetch:           config.Prefetch,
+		NetworkTip:         tip,
+	})
+
+	// Webhook subscriptions from config, more can be added through the API and are saved back to the file
+	webhooks := notify.NewRegistry(egressPolicy)
+	if config.WebhooksFile != "" {
+		if err := notify.LoadSubscriptionsFile(webhooks, config.WebhooksFile); err != nil {
+			return nil, err
+		}
+		webhooks.Persist(config.WebhooksFile)
+	}
+	dispatcher := notify.NewDispatcher(notify.DispatcherConfig{
+		Client: egress.NewPublicHTTPClient(egressPolicy, 10*time.Second),
+	}, webhooks, storage.NewFileDeadLetterStore(config.DeadLetters))
+
+	// Every run is recorded to correlate data gaps with restarts
+	sessions := session.NewTracker(storage.NewFileSessionStore(filepath.Join(config.CheckpointDir, "sessions.json")))
 
 	// Start background event consumer
-	go consumeEvents(usdcProcessor)
+	go consumeEvents(usdcProcessor, dispatcher)
+
+	idx := &Indexer{
+		config:            config,
+		clientConfig:      clientConfig,
+		networkTip:        tip,
+		rpcPool:           rpcPool,
+		ingestService:     ingestService,
+		processors:        processorList,
+		builtinProcessors: builtinProcessors,
+		usdcProcessor:     usdcProcessor,
+		failedTxs:         failedTxs,
+		priorityGate:      priorityGate,
+		dispatcher:        dispatcher,
+		ledgerBackend:     ledgerBackend,
+		sessions:          sessions,
+	}
+
+	// Retries run from API requests, next to the live lane, so they get their own processors
+	retryProcessors, err := idx.newLaneProcessors()
+	if err != nil {
+		return nil, err
+	}
+
+	deps := api.Dependencies{
+		Webhooks:   webhooks,
+		EventTypes: eventTypeProcessor,
+		Quarantine: decodeFailures,
+		Sessions:   sessions,
+		Status:     idx,
+		Health:     idx.newHealthChecker(),
+		Readiness:  idx.newReadinessChecker(),
+		FailedTxs: ingest.NewFailedTransactionRetrier(failedTxs, func() (rpc.LedgerBackendHandlerService, error) {
+			return newLedgerBackend(config, clientConfig)
+		}, retryProcessors, config.NetworkPass),
+	}
+
+	// Only the live lane can be paused or moved, and only its ledgers are summarized
+	if config.runsLive() {
+		deps.Ingestion = ingestService
+		deps.LedgerStats = ledgerStats
+	}
+
+	// Split backfills into chunks processed by a worker pool
+	if config.Backfill != nil {
+		idx.backfillCheckpoints = storage.NewFileCheckpointStore(filepath.Join(
+			config.CheckpointDir,
+			fmt.Sprintf("backfill_%d_%d", config.Backfill.Start, *config.Backfill.End),
+		))
+
+		coordinator, err := backfill.NewCoordinator(
+			context.Background(),
+			storage.NewFileBackfillStore(config.CheckpointDir),
+			idx.runBackfillChunk,
+			config.Backfill.Start,
+			*config.Backfill.End,
+			config.BackfillChunk,
+			config.BackfillPool,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating backfill coordinator: %w", err)
+		}
+		idx.backfill = coordinator
+		deps.Backfills = coordinator
+	}
+
+	if config.APIAddr != "" {
+		idx.apiServer = api.NewServer(config.APIAddr, deps, api.Options{
+			CacheMaxAge:     config.APICacheTTL,
+			APIKeys:         config.APIKeys,
+			RateLimit:       config.APIRateLimit,
+			RouteRateLimits: config.APIRouteRates,
+			CORSOrigins:     config.APICORSOrigins,
+			PublicUI:        config.APIPublicUI,
+		})
+	}
+
+	// Start the backend last: captive core runs a stellar-core process, which an earlier
+	// setup error would otherwise leave running
+	if err := ledgerBackend.Start(); err != nil {
+		return nil, fmt.Errorf("error starting ledger backend: %w", err)
+	}
 
-	return &Indexer{
-		ingestService: ingestService,
-		processors:    processorList,
-	}, nil
+	return idx, nil
 }
 
 // Start initializes and runs the indexer, blocking until a termination signal is received
 func (idx *Indexer) Start() error {
-	log.Printf("🚀 Starting indexer with RPC: %s")
+	log.Printf("🚀 Starting indexer with RPC: %s", idx.config.RPCEndpoint)
+
+	if idx.apiServer != nil {
+		idx.apiServer.Start()
+	}
+
+	// Start webhook delivery
+	idx.dispatcher.Start()
+
+	// Without a start ledger or checkpoint, follow the network from its tip, read from the same
+	// source as the lag reports (New rejects backends without one)
+	if idx.config.runsLive() && idx.config.StartLedger == 0 {
+		tip, err := idx.networkTip(context.Background())
+		if err != nil {
+			return fmt.Errorf("error getting the network tip to start from: %w", err)
+		}
+		idx.config.StartLedger = tip
+		log.Printf("📍 No start ledger or checkpoint, starting at the network tip %d", tip)
+	}
+
+	idx.beginSession()
 
 	// Start ingestion
-	if err := idx.ingestService.StartUnboundedRange(0); err != nil {
-		return fmt.Errorf("error starting ingest: %w", err)
+	if idx.config.runsLive() {
+		if err := idx.ingestService.StartUnboundedRange(idx.config.StartLedger); err != nil {
+			err = fmt.Errorf("error starting ingest: %w", err)
+			idx.endSession(session.ExitError, err)
+			return err
+		}
+	}
+
+	if idx.config.Backfill != nil {
+		return idx.runBackfill()
 	}
 
 	// Set up signal handling
@@ -76,26 +361,357 @@ func (idx *Indexer) Start() error {
 	log.Printf("📡 Signal received: %v", sig)
 
 	// Stop services
-	idx.Stop()
+	idx.stop(session.ExitSignal, nil)
 
 	return nil
 }
 
+// runBackfill processes the configured bounded range and returns once it completes or a termination signal is received
+func (idx *Indexer) runBackfill() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- idx.backfill.Run(ctx)
+	}()
+
+	// Set up signal handling
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	var err error
+	interrupted := false
+	select {
+	case err = <-done:
+	case sig := <-sigChan:
+		log.Printf("📡 Signal received: %v", sig)
+		interrupted = true
+		cancel()
+		<-done
+	}
+
+	// Write the final checkpoint once every chunk is done
+	if err == nil && idx.backfill.Snapshot().Done() {
+		if saveErr := idx.backfillCheckpoints.Save(ctx, *idx.config.Backfill.End); saveErr != nil {
+			err = fmt.Errorf("error saving final checkpoint: %w", saveErr)
+		}
+	}
+
+	// The live lane keeps running after the backfill until a termination signal
+	if err == nil && !interrupted && idx.config.Live {
+		log.Println("✅ Backfill finished, continuing with live ingestion")
+		sig := <-sigChan
+		log.Printf("📡 Signal received: %v", sig)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("error running backfill: %w", err)
+	}
+
+	switch {
+	case interrupted:
+		idx.stop(session.ExitSignal, nil)
+	case err != nil:
+		idx.stop(session.ExitError, err)
+	case idx.config.Live:
+		idx.stop(session.ExitSignal, nil)
+	default:
+		idx.stop(session.ExitBackfillCompleted, nil)
+	}
+
+	return err
+}
+
+// runBackfillChunk processes one backfill chunk with its own ledger backend, since a backend serves a single prepared range
+func (idx *Indexer) runBackfillChunk(ctx context.Context, start, end uint32) error {
+	ledgerBackend, err := newLedgerBackend(idx.config, idx.clientConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := ledgerBackend.Start(); err != nil {
+		return fmt.Errorf("error starting ledger backend: %w", err)
+	}
+	defer ledgerBackend.Close()
+
+	// Chunks run in parallel with each other and the live lane, each with its own processors
+	chunkProcessors, err := idx.newLaneProcessors()
+	if err != nil {
+		return err
+	}
+
+	chunkService := ingest.NewIngestService(ledgerBackend, chunkProcessors, ingest.Options{
+		NetworkPassphrase:  idx.config.NetworkPass,
+		FailedTransactions: idx.failedTxs,
+		PriorityGate:       idx.priorityGate,
+		TxTimeout:          idx.config.TxTimeout,
+		RetryPolicies:      idx.config.RetryPolicies,
+		Prefetch:           idx.config.Prefetch,
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- chunkService.RunBoundedRange(start, end)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		chunkService.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// newLaneProcessors returns the processors for one more lane: the shared built-in ones and
+// new instances of the custom ones, which pkg/processor never calls concurrently
+func (idx *Indexer) newLaneProcessors() ([]ingest.Processor, error) {
+	customProcessors, err := newCustomProcessors(idx.config.Processors)
+	if err != nil {
+		return nil, err
+	}
+	return append(slices.Clone(idx.builtinProcessors), customProcessors...), nil
+}
+
+// IngestTransaction processes a single transaction by hash, resolving its ledger through RPC.
+// Used to patch in a transaction that was missed without re-running a range.
+func (idx *Indexer) IngestTransaction(ctx context.Context, txHash string) error {
+	ledgerSeq, err := idx.clientConfig.LookupTransactionLedger(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🔎 Transaction %s is in ledger %d", txHash, ledgerSeq)
+
+	idx.dispatcher.Start()
+
+	newBackend := func() (rpc.LedgerBackendHandlerService, error) {
+		return newLedgerBackend(idx.config, idx.clientConfig)
+	}
+	ingestErr := ingest.IngestTransaction(ctx, newBackend, idx.processors, idx.config.NetworkPass, ledgerSeq, txHash)
+
+	// Hand the events to the webhook dispatcher and wait for their delivery
+	idx.drainEvents()
+
+	return ingestErr
+}
+
 // Stop gracefully shuts down the indexer by stopping the ingest service and closing the ledger backend
 func (idx *Indexer) Stop() {
+	idx.stop(session.ExitStopped, nil)
+}
+
+// stop shuts the indexer down and closes the session with the reason for exiting
+func (idx *Indexer) stop(reason string, exitErr error) {
 	log.Println("🛑 Stopping indexer...")
 
-	// Stop ingestion
+	// Stop ingestion, flushing processors and saving the final checkpoint
 	idx.ingestService.Stop()
 
+	// Close the ledger backend only once nothing reads from it
+	if err := idx.ledgerBackend.Close(); err != nil {
+		log.Printf("⚠️  Error closing ledger backend: %v", err)
+	}
+
+	idx.endSession(reason, exitErr)
+
+	// Deliver the webhooks of the events flushed above
+	idx.drainEvents()
+
+	// Stop API server
+	if idx.apiServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := idx.apiServer.Stop(ctx); err != nil {
+			log.Printf("⚠️  Error stopping API server: %v", err)
+		}
+	}
+
 	log.Println("✅ Indexer stopped")
 }
 
+// drainEvents waits for buffered events to reach the dispatcher and for their webhooks to be delivered,
+// dead-lettering whatever is left after drainTimeout, then stops the dispatcher
+func (idx *Indexer) drainEvents() {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	if err := idx.usdcProcessor.Flush(ctx); err != nil {
+		log.Printf("⚠️  Error flushing events: %v", err)
+	}
+	idx.dispatcher.Drain(ctx)
+}
+
+// beginSession records the start of this run. Failures only cost the history, so they are logged.
+func (idx *Indexer) beginSession() {
+	startLedger := idx.config.StartLedger
+	if !idx.config.runsLive() {
+		startLedger = idx.config.Backfill.Start
+	}
+
+	if err := idx.sessions.Begin(context.Background(), idx.Mode(), startLedger); err != nil {
+		log.Printf("⚠️  Error recording session start: %v", err)
+	}
+}
+
+// endSession records the end of this run with the last processed ledger
+func (idx *Indexer) endSession(reason string, exitErr error) {
+	var endLedger uint32
+	switch {
+	case idx.config.runsLive():
+		endLedger = idx.ingestService.LastLedger()
+	case idx.backfill.Snapshot().Done():
+		endLedger = *idx.config.Backfill.End
+	}
+
+	if err := idx.sessions.End(context.Background(), endLedger, reason, exitErr); err != nil {
+		log.Printf("⚠️  Error recording session end: %v", err)
+	}
+}
+
+// newLedgerBackend creates the handler for the configured ledger source
+func newLedgerBackend(config Config, clientConfig rpc_backend.ClientConfig) (rpc.LedgerBackendHandlerService, error) {
+	switch config.LedgerBackend {
+	case "", LedgerBackendRPC:
+		return &rpc.LedgerBackend{
+			ClientConfig: clientConfig,
+		}, nil
+	case LedgerBackendDataLake:
+		return &datalake.LedgerBackend{
+			ClientConfig: config.DataLake,
+		}, nil
+	case LedgerBackendHybrid:
+		return &hybrid.LedgerBackend{
+			Archive:         &datalake.LedgerBackend{ClientConfig: config.DataLake},
+			Live:            &rpc.LedgerBackend{ClientConfig: clientConfig},
+			HandoffDistance: config.HandoffLedger,
+			NetworkTip:      networkTip(config, clientConfig),
+		}, nil
+	case LedgerBackendCaptive:
+		captiveConfig := config.CaptiveCore
+		captiveConfig.NetworkPassphrase = config.NetworkPass
+		return &captivecore.LedgerBackend{
+			ClientConfig: captiveConfig,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown ledger backend %q", config.LedgerBackend)
+	}
+}
+
+// networkTip reads the network tip from RPC getHealth for the backends that stream from RPC,
+// whose own latest ledger is only the end of the RPC buffer. Other backends report it themselves.
+func networkTip(config Config, clientConfig rpc_backend.ClientConfig) ingest.NetworkTipFunc {
+	switch config.LedgerBackend {
+	case "", LedgerBackendRPC, LedgerBackendHybrid:
+		return func(ctx context.Context) (uint32, error) {
+			health, err := clientConfig.GetHealth(ctx)
+			if err != nil {
+				return 0, err
+			}
+			return health.LatestLedger, nil
+		}
+	default:
+		return nil
+	}
+}
+
+// checkEgress verifies that the configured ledger source is reachable under the egress policy.
+// Object storage and captive core archives are only checked here, by host: the storage SDKs and
+// the stellar-core subprocess don't go through the egress HTTP client.
+func checkEgress(config Config, policy *egress.Policy) error {
+	if !policy.Enabled() {
+		return nil
+	}
+
+	switch config.LedgerBackend {
+	case "", LedgerBackendRPC:
+		return checkRPCEgress(config.RPCEndpoint, policy)
+	case LedgerBackendDataLake:
+		return policy.CheckHost(dataLakeHost(config.DataLake.DataStore))
+	case LedgerBackendHybrid:
+		if err := checkRPCEgress(config.RPCEndpoint, policy); err != nil {
+			return err
+		}
+		return policy.CheckHost(dataLakeHost(config.DataLake.DataStore))
+	case LedgerBackendCaptive:
+		captiveConfig := config.CaptiveCore
+		captiveConfig.NetworkPassphrase = config.NetworkPass
+		archives, err := (&captivecore_backend.LedgerBuilder{ClientConfig: captiveConfig}).HistoryArchiveURLs()
+		if err != nil {
+			return err
+		}
+		for _, archive := range archives {
+			if err := policy.CheckURL(archive); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRPCEgress verifies every RPC endpoint of the failover list
+func checkRPCEgress(endpoints string, policy *egress.Policy) error {
+	for _, endpoint := range rpc_backend.SplitEndpoints(endpoints) {
+		if err := policy.CheckURL(endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataLakeHost returns the object storage host the data lake backend connects to
+func dataLakeHost(config datalake_backend.DataStoreConfig) string {
+	if config.Endpoint != "" {
+		if parsed, err := url.Parse(config.Endpoint); err == nil && parsed.Hostname() != "" {
+			return parsed.Hostname()
+		}
+		return config.Endpoint
+	}
+
+	if strings.EqualFold(config.Type, "S3") {
+		if config.Region != "" {
+			return fmt.Sprintf("s3.%s.amazonaws.com", config.Region)
+		}
+		return "s3.amazonaws.com"
+	}
+
+	return "storage.googleapis.com"
+}
+
+// Mode returns which lanes this run processes: live, backfill or live+backfill
+func (idx *Indexer) Mode() string {
+	switch {
+	case idx.config.Backfill == nil:
+		return "live"
+	case idx.config.Live:
+		return "live+backfill"
+	default:
+		return "backfill"
+	}
+}
+
+// Progress returns the position, lag and rate of the live lane
+func (idx *Indexer) Progress() ingest.Progress {
+	return idx.ingestService.Progress()
+}
+
+// runsLive reports whether the live streaming lane is enabled
+func (c Config) runsLive() bool {
+	return c.Backfill == nil || c.Live
+}
+
 // consumeEvents continuously processes events from the processor's buffer channel
-func consumeEvents(processor *processors.USDCTransferProcessor) {
+func consumeEvents(processor *processors.USDCTransferProcessor, dispatcher *notify.Dispatcher) {
 	for event := range processor.GetBuffer() {
 		// Currently just logging, will persist later
 		log.Printf("📊 USDC event processed: %+v", event)
 		// TODO: Add persistence logic to MongoDB here
+
+		// Notify webhook subscribers
+		dispatcher.Publish(event.Type, event)
+		metrics.ObserveEventLatency(event.Type, event.LedgerCloseTime)
 	}
 }
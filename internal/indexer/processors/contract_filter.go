@@ -0,0 +1,88 @@
+package processors
+
+import (
+	"context"
+	"strings"
+
+	"indexer/pkg/processor"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// ContractFilter hands a processor only the transactions that invoke or emit events from given contracts
+type ContractFilter struct {
+	inner    processor.Processor
+	prefixes []string
+}
+
+// NewContractFilter wraps p; contracts are contract IDs or ID prefixes
+func NewContractFilter(p processor.Processor, contracts []string) *ContractFilter {
+	return &ContractFilter{inner: p, prefixes: contracts}
+}
+
+func (f *ContractFilter) Name() string {
+	return f.inner.Name()
+}
+
+// ProcessLedger always reaches the processor
+func (f *ContractFilter) ProcessLedger(ctx context.Context, ledger xdr.LedgerCloseMeta) error {
+	return f.inner.ProcessLedger(ctx, ledger)
+}
+
+// ProcessTransaction forwards the transaction if it touches one of the contracts
+func (f *ContractFilter) ProcessTransaction(ctx context.Context, tx ingest.LedgerTransaction) error {
+	if !f.touches(tx) {
+		return nil
+	}
+	return f.inner.ProcessTransaction(ctx, tx)
+}
+
+// Flush forwards to processors that buffer state
+func (f *ContractFilter) Flush(ctx context.Context) error {
+	if flushable, ok := f.inner.(interface{ Flush(context.Context) error }); ok {
+		return flushable.Flush(ctx)
+	}
+	return nil
+}
+
+// touches reports whether the transaction invokes a matching contract or one emits an event in it
+func (f *ContractFilter) touches(tx ingest.LedgerTransaction) bool {
+	ops := tx.Envelope.Operations()
+	if len(ops) == 1 {
+		if op, ok := ops[0].Body.GetInvokeHostFunctionOp(); ok {
+			if call, ok := op.HostFunction.GetInvokeContract(); ok {
+				if contractID, err := call.ContractAddress.String(); err == nil && f.matches(contractID) {
+					return true
+				}
+			}
+		}
+	}
+
+	events, err := tx.GetContractEvents()
+	if err != nil {
+		return false
+	}
+	for _, event := range events {
+		if event.ContractId == nil {
+			continue
+		}
+		contractID, err := strkey.Encode(strkey.VersionByteContract, event.ContractId[:])
+		if err == nil && f.matches(contractID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks a contract ID against the configured IDs and prefixes
+func (f *ContractFilter) matches(contractID string) bool {
+	for _, prefix := range f.prefixes {
+		if strings.HasPrefix(contractID, prefix) {
+			return true
+		}
+	}
+	return false
+}
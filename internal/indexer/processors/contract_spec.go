@@ -0,0 +1,410 @@
+package processors
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// wasmMagic starts every WASM module
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+// specSection is the WASM custom section holding the SEP-48 contract spec
+const specSection = "contractspecv0"
+
+// DecodedEvent is a contract event decoded with the contract's spec
+type DecodedEvent struct {
+	Name   string         `json:"name"`
+	Fields map[string]any `json:"fields"`
+}
+
+// DecodedInvocation is a contract call decoded with the contract's spec
+type DecodedInvocation struct {
+	ContractID string         `json:"contract_id"`
+	Function   string         `json:"function"`
+	Args       map[string]any `json:"args"`
+}
+
+// ContractSpec is the interface of a contract, read from its SEP-48 spec entries
+type ContractSpec struct {
+	functions map[string]xdr.ScSpecFunctionV0
+	events    []xdr.ScSpecEventV0
+	structs   map[string]xdr.ScSpecUdtStructV0
+	unions    map[string]xdr.ScSpecUdtUnionV0
+	enums     map[string][]xdr.ScSpecUdtEnumCaseV0
+}
+
+// ParseContractSpec reads a stream of XDR ScSpecEntry, as stored in the contractspecv0 section
+func ParseContractSpec(data []byte) (*ContractSpec, error) {
+	spec := &ContractSpec{
+		functions: make(map[string]xdr.ScSpecFunctionV0),
+		structs:   make(map[string]xdr.ScSpecUdtStructV0),
+		unions:    make(map[string]xdr.ScSpecUdtUnionV0),
+		enums:     make(map[string][]xdr.ScSpecUdtEnumCaseV0),
+	}
+
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		var entry xdr.ScSpecEntry
+		if _, err := xdr.Unmarshal(reader, &entry); err != nil {
+			return nil, fmt.Errorf("invalid spec entry: %w", err)
+		}
+		spec.add(entry)
+	}
+
+	return spec, nil
+}
+
+// ContractSpecFromWasm reads the spec embedded in a contract's WASM
+func ContractSpecFromWasm(wasm []byte) (*ContractSpec, error) {
+	if len(wasm) < 8 || !bytes.Equal(wasm[:4], wasmMagic) {
+		return nil, fmt.Errorf("not a WASM module")
+	}
+
+	var data []byte
+	for rest := wasm[8:]; len(rest) > 0; {
+		id := rest[0]
+		size, n := binary.Uvarint(rest[1:])
+		if n <= 0 || size > uint64(len(rest)-1-n) {
+			return nil, fmt.Errorf("truncated WASM section")
+		}
+		body := rest[1+n : 1+n+int(size)]
+		rest = rest[1+n+int(size):]
+
+		// Custom sections (id 0) start with their name
+		if id != 0 {
+			continue
+		}
+		nameLen, m := binary.Uvarint(body)
+		if m <= 0 || nameLen > uint64(len(body)-m) {
+			return nil, fmt.Errorf("truncated WASM custom section")
+		}
+		if string(body[m:m+int(nameLen)]) == specSection {
+			data = append(data, body[m+int(nameLen):]...)
+		}
+	}
+
+	if data == nil {
+		return nil, fmt.Errorf("WASM has no %s section", specSection)
+	}
+	return ParseContractSpec(data)
+}
+
+// LoadContractSpecFile reads a spec from a contract WASM, a base64 XDR stream,
+// or a JSON array of base64 entries (stellar contract info interface --output xdr-base64-array)
+func LoadContractSpecFile(path string) (*ContractSpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading contract spec: %w", err)
+	}
+
+	if bytes.HasPrefix(content, wasmMagic) {
+		return ContractSpecFromWasm(content)
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	if strings.HasPrefix(trimmed, "[") {
+		var entries []string
+		if err := json.Unmarshal([]byte(trimmed), &entries); err != nil {
+			return nil, fmt.Errorf("invalid contract spec %s: %w", path, err)
+		}
+		var stream []byte
+		for _, entry := range entries {
+			raw, err := base64.StdEncoding.DecodeString(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid contract spec %s: %w", path, err)
+			}
+			stream = append(stream, raw...)
+		}
+		return ParseContractSpec(stream)
+	}
+
+	stream, err := base64.StdEncoding.DecodeString(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract spec %s: %w", path, err)
+	}
+	return ParseContractSpec(stream)
+}
+
+// add indexes one spec entry
+func (s *ContractSpec) add(entry xdr.ScSpecEntry) {
+	switch entry.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+		s.functions[string(entry.FunctionV0.Name)] = *entry.FunctionV0
+	case xdr.ScSpecEntryKindScSpecEntryEventV0:
+		s.events = append(s.events, *entry.EventV0)
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		s.structs[entry.UdtStructV0.Name] = *entry.UdtStructV0
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		s.unions[entry.UdtUnionV0.Name] = *entry.UdtUnionV0
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		s.enums[entry.UdtEnumV0.Name] = entry.UdtEnumV0.Cases
+	case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		cases := make([]xdr.ScSpecUdtEnumCaseV0, 0, len(entry.UdtErrorEnumV0.Cases))
+		for _, c := range entry.UdtErrorEnumV0.Cases {
+			cases = append(cases, xdr.ScSpecUdtEnumCaseV0{Name: c.Name, Value: c.Value})
+		}
+		s.enums[entry.UdtErrorEnumV0.Name] = cases
+	}
+}
+
+// DecodeEvent names the topics and data of an event declared in the spec
+func (s *ContractSpec) DecodeEvent(topics []xdr.ScVal, data xdr.ScVal) (DecodedEvent, bool) {
+	event, prefixLen, ok := s.matchEvent(topics)
+	if !ok {
+		return DecodedEvent{}, false
+	}
+
+	decoded := DecodedEvent{Name: string(event.Name), Fields: make(map[string]any, len(event.Params))}
+
+	// Topic params follow the prefix topics in declaration order
+	topicIndex := prefixLen
+	var dataParams []xdr.ScSpecEventParamV0
+	for _, param := range event.Params {
+		if param.Location != xdr.ScSpecEventParamLocationV0ScSpecEventParamLocationTopicList {
+			dataParams = append(dataParams, param)
+			continue
+		}
+		if topicIndex < len(topics) {
+			decoded.Fields[param.Name] = s.decodeValue(param.Type, topics[topicIndex])
+		}
+		topicIndex++
+	}
+
+	switch event.DataFormat {
+	case xdr.ScSpecEventDataFormatScSpecEventDataFormatSingleValue:
+		if len(dataParams) == 1 {
+			decoded.Fields[dataParams[0].Name] = s.decodeValue(dataParams[0].Type, data)
+		}
+	case xdr.ScSpecEventDataFormatScSpecEventDataFormatVec:
+		if vec, ok := data.GetVec(); ok && vec != nil {
+			for i, param := range dataParams {
+				if i < len(*vec) {
+					decoded.Fields[param.Name] = s.decodeValue(param.Type, (*vec)[i])
+				}
+			}
+		}
+	case xdr.ScSpecEventDataFormatScSpecEventDataFormatMap:
+		for _, param := range dataParams {
+			if val, ok := mapValue(data, param.Name); ok {
+				decoded.Fields[param.Name] = s.decodeValue(param.Type, val)
+			}
+		}
+	}
+
+	return decoded, true
+}
+
+// DecodeInvocation names the arguments of a call to a function declared in the spec
+func (s *ContractSpec) DecodeInvocation(function string, args []xdr.ScVal) (map[string]any, bool) {
+	fn, ok := s.functions[function]
+	if !ok {
+		return nil, false
+	}
+
+	decoded := make(map[string]any, len(fn.Inputs))
+	for i, input := range fn.Inputs {
+		if i < len(args) {
+			decoded[input.Name] = s.decodeValue(input.Type, args[i])
+		}
+	}
+	return decoded, true
+}
+
+// matchEvent returns the event whose prefix topics match, preferring the longest prefix, and the prefix length.
+// Events without prefix topics are matched by their name.
+func (s *ContractSpec) matchEvent(topics []xdr.ScVal) (xdr.ScSpecEventV0, int, bool) {
+	var best xdr.ScSpecEventV0
+	bestLen := 0
+	found := false
+
+	for _, event := range s.events {
+		prefix := event.PrefixTopics
+		if len(prefix) == 0 {
+			prefix = []xdr.ScSymbol{event.Name}
+		}
+		if len(prefix) > len(topics) || (found && len(prefix) <= bestLen) {
+			continue
+		}
+
+		matches := true
+		for i, symbol := range prefix {
+			if sym, ok := topics[i].GetSym(); !ok || sym != symbol {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			best, bestLen, found = event, len(prefix), true
+		}
+	}
+
+	return best, bestLen, found
+}
+
+// decodeValue converts a value of a spec type, naming struct fields, union cases and enum values
+func (s *ContractSpec) decodeValue(def xdr.ScSpecTypeDef, val xdr.ScVal) any {
+	switch def.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if val.Type == xdr.ScValTypeScvVoid {
+			return nil
+		}
+		return s.decodeValue(def.Option.ValueType, val)
+
+	case xdr.ScSpecTypeScSpecTypeVec:
+		if vec, ok := val.GetVec(); ok && vec != nil {
+			items := make([]any, 0, len(*vec))
+			for _, item := range *vec {
+				items = append(items, s.decodeValue(def.Vec.ElementType, item))
+			}
+			return items
+		}
+
+	case xdr.ScSpecTypeScSpecTypeMap:
+		if scMap, ok := val.GetMap(); ok && scMap != nil {
+			entries := make(map[string]any, len(*scMap))
+			for _, entry := range *scMap {
+				key := fmt.Sprint(s.decodeValue(def.Map.KeyType, entry.Key))
+				entries[key] = s.decodeValue(def.Map.ValueType, entry.Val)
+			}
+			return entries
+		}
+
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		if vec, ok := val.GetVec(); ok && vec != nil {
+			items := make([]any, 0, len(*vec))
+			for i, item := range *vec {
+				if i < len(def.Tuple.ValueTypes) {
+					items = append(items, s.decodeValue(def.Tuple.ValueTypes[i], item))
+				} else {
+					items = append(items, scValToInterface(item))
+				}
+			}
+			return items
+		}
+
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if decoded, ok := s.decodeUdt(def.Udt.Name, val); ok {
+			return decoded
+		}
+	}
+
+	return scValToInterface(val)
+}
+
+// decodeUdt converts a value of a user defined type
+func (s *ContractSpec) decodeUdt(name string, val xdr.ScVal) (any, bool) {
+	if udt, ok := s.structs[name]; ok {
+		// Tuple structs have numbered fields and are encoded as a vec
+		if len(udt.Fields) > 0 && udt.Fields[0].Name == "0" {
+			vec, ok := val.GetVec()
+			if !ok || vec == nil {
+				return nil, false
+			}
+			items := make([]any, 0, len(*vec))
+			for i, item := range *vec {
+				if i < len(udt.Fields) {
+					items = append(items, s.decodeValue(udt.Fields[i].Type, item))
+				}
+			}
+			return items, true
+		}
+
+		fields := make(map[string]any, len(udt.Fields))
+		for _, field := range udt.Fields {
+			if fieldVal, ok := mapValue(val, field.Name); ok {
+				fields[field.Name] = s.decodeValue(field.Type, fieldVal)
+			}
+		}
+		return fields, true
+	}
+
+	if udt, ok := s.unions[name]; ok {
+		// Unions are a vec of the case name followed by its values
+		vec, ok := val.GetVec()
+		if !ok || vec == nil || len(*vec) == 0 {
+			return nil, false
+		}
+		caseName, ok := (*vec)[0].GetSym()
+		if !ok {
+			return nil, false
+		}
+		for _, c := range udt.Cases {
+			if c.TupleCase == nil || c.TupleCase.Name != string(caseName) {
+				continue
+			}
+			values := make([]any, 0, len(*vec)-1)
+			for i, item := range (*vec)[1:] {
+				if i < len(c.TupleCase.Type) {
+					values = append(values, s.decodeValue(c.TupleCase.Type[i], item))
+				}
+			}
+			if len(values) == 1 {
+				return map[string]any{string(caseName): values[0]}, true
+			}
+			return map[string]any{string(caseName): values}, true
+		}
+		return string(caseName), true
+	}
+
+	if cases, ok := s.enums[name]; ok {
+		if value, ok := val.GetU32(); ok {
+			for _, c := range cases {
+				if c.Value == value {
+					return c.Name, true
+				}
+			}
+			return strconv.FormatUint(uint64(value), 10), true
+		}
+	}
+
+	return nil, false
+}
+
+// mapValue returns the entry of a ScVal map keyed by the given symbol
+func mapValue(val xdr.ScVal, key string) (xdr.ScVal, bool) {
+	scMap, ok := val.GetMap()
+	if !ok || scMap == nil {
+		return xdr.ScVal{}, false
+	}
+	for _, entry := range *scMap {
+		if sym, ok := entry.Key.GetSym(); ok && string(sym) == key {
+			return entry.Val, true
+		}
+	}
+	return xdr.ScVal{}, false
+}
+
+// ContractSpecs holds the specs of the contracts whose payloads are decoded
+type ContractSpecs struct {
+	specs map[string]*ContractSpec
+}
+
+// LoadContractSpecs loads one spec file per contract ID (see LoadContractSpecFile)
+func LoadContractSpecs(files map[string]string) (*ContractSpecs, error) {
+	specs := &ContractSpecs{specs: make(map[string]*ContractSpec, len(files))}
+	for contractID, path := range files {
+		spec, err := LoadContractSpecFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %w", contractID, err)
+		}
+		specs.specs[contractID] = spec
+	}
+	return specs, nil
+}
+
+// Lookup returns the spec of a contract, nil-safe
+func (c *ContractSpecs) Lookup(contractID string) (*ContractSpec, bool) {
+	if c == nil {
+		return nil, false
+	}
+	spec, ok := c.specs[contractID]
+	return spec, ok
+}
@@ -0,0 +1,411 @@
+package processors
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ScVal builders
+
+func symVal(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func strVal(s string) xdr.ScVal {
+	str := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}
+}
+
+func u32Val(v uint32) xdr.ScVal {
+	u := xdr.Uint32(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}
+}
+
+func u64Val(v uint64) xdr.ScVal {
+	u := xdr.Uint64(v)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}
+}
+
+func vecVal(items ...xdr.ScVal) xdr.ScVal {
+	vec := xdr.ScVec(items)
+	ptr := &vec
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &ptr}
+}
+
+// mapVal builds a symbol keyed map from alternating keys and values
+func mapVal(pairs ...any) xdr.ScVal {
+	var entries xdr.ScMap
+	for i := 0; i < len(pairs); i += 2 {
+		entries = append(entries, xdr.ScMapEntry{Key: symVal(pairs[i].(string)), Val: pairs[i+1].(xdr.ScVal)})
+	}
+	ptr := &entries
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &ptr}
+}
+
+// Spec type builders
+
+func specType(t xdr.ScSpecType) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: t}
+}
+
+func udtType(name string) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: name}}
+}
+
+func optionType(value xdr.ScSpecTypeDef) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: value}}
+}
+
+// escrowSpec is the spec of a milestone escrow contract covering every entry kind the decoder handles
+func escrowSpec() []xdr.ScSpecEntry {
+	u32, u64, str := specType(xdr.ScSpecTypeScSpecTypeU32), specType(xdr.ScSpecTypeScSpecTypeU64), specType(xdr.ScSpecTypeScSpecTypeString)
+	topic, data := xdr.ScSpecEventParamLocationV0ScSpecEventParamLocationTopicList, xdr.ScSpecEventParamLocationV0ScSpecEventParamLocationData
+
+	return []xdr.ScSpecEntry{
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtEnumV0, UdtEnumV0: &xdr.ScSpecUdtEnumV0{
+			Name: "Status",
+			Cases: []xdr.ScSpecUdtEnumCaseV0{
+				{Name: "Pending", Value: 0},
+				{Name: "Approved", Value: 1},
+				{Name: "Released", Value: 2},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0, UdtErrorEnumV0: &xdr.ScSpecUdtErrorEnumV0{
+			Name:  "EscrowError",
+			Cases: []xdr.ScSpecUdtErrorEnumCaseV0{{Name: "NotFound", Value: 1}},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0, UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Milestone",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "amount", Type: u64},
+				{Name: "description", Type: str},
+				{Name: "status", Type: udtType("Status")},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0, UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name:   "Split",
+			Fields: []xdr.ScSpecUdtStructFieldV0{{Name: "0", Type: u32}, {Name: "1", Type: udtType("Status")}},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0, UdtUnionV0: &xdr.ScSpecUdtUnionV0{
+			Name: "Resolution",
+			Cases: []xdr.ScSpecUdtUnionCaseV0{
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0, VoidCase: &xdr.ScSpecUdtUnionCaseVoidV0{Name: "Cancelled"}},
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0, TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{Name: "Refund", Type: []xdr.ScSpecTypeDef{u64}}},
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0, TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{Name: "Split", Type: []xdr.ScSpecTypeDef{u64, udtType("Status")}}},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "release",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "escrow_id", Type: u64},
+				{Name: "milestone", Type: udtType("Milestone")},
+				{Name: "memo", Type: optionType(str)},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryEventV0, EventV0: &xdr.ScSpecEventV0{
+			Name:         "tw_release",
+			PrefixTopics: []xdr.ScSymbol{"tw_release"},
+			Params: []xdr.ScSpecEventParamV0{
+				{Name: "escrow_id", Type: u64, Location: topic},
+				{Name: "milestone", Type: udtType("Milestone"), Location: data},
+			},
+			DataFormat: xdr.ScSpecEventDataFormatScSpecEventDataFormatSingleValue,
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryEventV0, EventV0: &xdr.ScSpecEventV0{
+			Name:         "tw_dispute",
+			PrefixTopics: []xdr.ScSymbol{"tw_dispute"},
+			Params: []xdr.ScSpecEventParamV0{
+				{Name: "resolution", Type: udtType("Resolution"), Location: data},
+				{Name: "reason", Type: str, Location: data},
+			},
+			DataFormat: xdr.ScSpecEventDataFormatScSpecEventDataFormatMap,
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryEventV0, EventV0: &xdr.ScSpecEventV0{
+			Name:         "tw_split",
+			PrefixTopics: []xdr.ScSymbol{"tw_dispute", "split"},
+			Params: []xdr.ScSpecEventParamV0{
+				{Name: "shares", Type: udtType("Split"), Location: data},
+				{Name: "status", Type: udtType("EscrowError"), Location: data},
+			},
+			DataFormat: xdr.ScSpecEventDataFormatScSpecEventDataFormatVec,
+		}},
+	}
+}
+
+// specStream encodes entries as the XDR stream stored in the contractspecv0 section
+func specStream(t *testing.T, entries []xdr.ScSpecEntry) []byte {
+	t.Helper()
+
+	var stream []byte
+	for _, entry := range entries {
+		raw, err := entry.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream = append(stream, raw...)
+	}
+	return stream
+}
+
+// wasmSection encodes a section: its id, its LEB128 size and its body
+func wasmSection(id byte, body []byte) []byte {
+	section := binary.AppendUvarint([]byte{id}, uint64(len(body)))
+	return append(section, body...)
+}
+
+// customSection encodes a custom section, whose body starts with its name
+func customSection(name string, payload []byte) []byte {
+	body := binary.AppendUvarint(nil, uint64(len(name)))
+	body = append(body, name...)
+	return wasmSection(0, append(body, payload...))
+}
+
+// wasmModule assembles a module from the WASM header and sections
+func wasmModule(sections ...[]byte) []byte {
+	module := append([]byte{}, wasmMagic...)
+	module = append(module, 1, 0, 0, 0) // Version 1
+	for _, section := range sections {
+		module = append(module, section...)
+	}
+	return module
+}
+
+// contractWasm lays out a module the way soroban-sdk builds contracts: an exported function
+// with its type and code, then the contractenvmetav0, contractmetav0 and contractspecv0 sections
+func contractWasm(spec []byte) []byte {
+	return wasmModule(
+		wasmSection(1, []byte{1, 0x60, 0, 1, 0x7e}),                           // Type: () -> i64
+		wasmSection(3, []byte{1, 0}),                                          // Function 0 has type 0
+		wasmSection(7, []byte{1, 7, 'r', 'e', 'l', 'e', 'a', 's', 'e', 0, 0}), // Export "release"
+		wasmSection(10, []byte{1, 4, 0, 0x42, 0, 0x0b}),                       // Code: i64.const 0
+		customSection("contractenvmetav0", []byte{0, 0, 0, 0, 0, 0, 0, 23, 0, 0, 0, 0}),
+		customSection("contractmetav0", []byte{0, 0, 0, 0}),
+		customSection(specSection, spec),
+	)
+}
+
+func TestContractSpecFromWasm(t *testing.T) {
+	spec := specStream(t, escrowSpec())
+	half := len(specStream(t, escrowSpec()[:4]))
+
+	tests := []struct {
+		name    string
+		wasm    []byte
+		wantErr string
+	}{
+		{"contract", contractWasm(spec), ""},
+		{"spec split across sections", wasmModule(customSection(specSection, spec[:half]), customSection(specSection, spec[half:])), ""},
+		{"not wasm", []byte("\x7fELF\x02\x01\x01\x00"), "not a WASM module"},
+		{"too short", wasmMagic, "not a WASM module"},
+		{"truncated section", contractWasm(spec)[:len(contractWasm(spec))-10], "truncated WASM section"},
+		{"truncated custom section name", wasmModule(wasmSection(0, []byte{20, 'c', 'o', 'n'})), "truncated WASM custom section"},
+		{"missing spec section", wasmModule(customSection("contractenvmetav0", []byte{0, 0, 0, 0})), "no contractspecv0 section"},
+		{"invalid spec entry", wasmModule(customSection(specSection, []byte{0, 0, 0, 99})), "invalid spec entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ContractSpecFromWasm(tt.wasm)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ContractSpecFromWasm() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ContractSpecFromWasm() error = %v", err)
+			}
+			if len(got.functions) != 1 || len(got.events) != 3 || len(got.structs) != 2 || len(got.unions) != 1 || len(got.enums) != 2 {
+				t.Errorf("spec has %d functions, %d events, %d structs, %d unions, %d enums, want 1, 3, 2, 1, 2",
+					len(got.functions), len(got.events), len(got.structs), len(got.unions), len(got.enums))
+			}
+		})
+	}
+}
+
+func TestParseContractSpec(t *testing.T) {
+	stream := specStream(t, escrowSpec())
+
+	if _, err := ParseContractSpec(stream[:len(stream)-3]); err == nil {
+		t.Error("truncated stream parsed")
+	}
+
+	empty, err := ParseContractSpec(nil)
+	if err != nil {
+		t.Fatalf("empty stream: %v", err)
+	}
+	if _, ok := empty.DecodeInvocation("release", nil); ok {
+		t.Error("empty spec declares release")
+	}
+}
+
+func TestLoadContractSpecFile(t *testing.T) {
+	stream := specStream(t, escrowSpec())
+
+	entries := make([]string, 0, len(escrowSpec()))
+	for _, entry := range escrowSpec() {
+		raw, err := entry.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, base64.StdEncoding.EncodeToString(raw))
+	}
+	array, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		valid   bool
+	}{
+		{"wasm", contractWasm(stream), true},
+		{"base64 stream", []byte(base64.StdEncoding.EncodeToString(stream) + "\n"), true},
+		{"json array", append([]byte("\n"), array...), true},
+		{"invalid base64", []byte("not base64!"), false},
+		{"invalid json", []byte(`["AAAA",`), false},
+		{"invalid entry in json array", []byte(`["%%%"]`), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec")
+			if err := os.WriteFile(path, tt.content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			spec, err := LoadContractSpecFile(path)
+			if !tt.valid {
+				if err == nil {
+					t.Error("LoadContractSpecFile() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadContractSpecFile() error = %v", err)
+			}
+			if _, ok := spec.DecodeInvocation("release", nil); !ok {
+				t.Error("loaded spec doesn't declare release")
+			}
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	spec, err := ParseContractSpec(specStream(t, escrowSpec()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	milestone := mapVal("amount", u64Val(500), "description", strVal("design"), "status", u32Val(1))
+
+	tests := []struct {
+		name   string
+		topics []xdr.ScVal
+		data   xdr.ScVal
+		want   *DecodedEvent // nil = not declared
+	}{
+		{
+			name:   "struct with an enum field",
+			topics: []xdr.ScVal{symVal("tw_release"), u64Val(7)},
+			data:   milestone,
+			want: &DecodedEvent{Name: "tw_release", Fields: map[string]any{
+				"escrow_id": uint64(7),
+				"milestone": map[string]any{"amount": uint64(500), "description": "design", "status": "Approved"},
+			}},
+		},
+		{
+			name:   "union case with one value",
+			topics: []xdr.ScVal{symVal("tw_dispute")},
+			data:   mapVal("resolution", vecVal(symVal("Refund"), u64Val(300)), "reason", strVal("late")),
+			want: &DecodedEvent{Name: "tw_dispute", Fields: map[string]any{
+				"resolution": map[string]any{"Refund": uint64(300)},
+				"reason":     "late",
+			}},
+		},
+		{
+			name:   "union case with several values",
+			topics: []xdr.ScVal{symVal("tw_dispute")},
+			data:   mapVal("resolution", vecVal(symVal("Split"), u64Val(100), u32Val(2))),
+			want: &DecodedEvent{Name: "tw_dispute", Fields: map[string]any{
+				"resolution": map[string]any{"Split": []any{uint64(100), "Released"}},
+			}},
+		},
+		{
+			name:   "void union case",
+			topics: []xdr.ScVal{symVal("tw_dispute")},
+			data:   mapVal("resolution", vecVal(symVal("Cancelled"))),
+			want:   &DecodedEvent{Name: "tw_dispute", Fields: map[string]any{"resolution": "Cancelled"}},
+		},
+		{
+			name:   "longest prefix, tuple struct and error enum in a vec",
+			topics: []xdr.ScVal{symVal("tw_dispute"), symVal("split")},
+			data:   vecVal(vecVal(u32Val(60), u32Val(0)), u32Val(1)),
+			want: &DecodedEvent{Name: "tw_split", Fields: map[string]any{
+				"shares": []any{uint32(60), "Pending"},
+				"status": "NotFound",
+			}},
+		},
+		{
+			name:   "enum value outside the spec",
+			topics: []xdr.ScVal{symVal("tw_release"), u64Val(7)},
+			data:   mapVal("status", u32Val(9)),
+			want: &DecodedEvent{Name: "tw_release", Fields: map[string]any{
+				"escrow_id": uint64(7),
+				"milestone": map[string]any{"status": "9"},
+			}},
+		},
+		{
+			name:   "undeclared event",
+			topics: []xdr.ScVal{symVal("transfer")},
+			data:   u64Val(1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := spec.DecodeEvent(tt.topics, tt.data)
+			if tt.want == nil {
+				if ok {
+					t.Errorf("DecodeEvent() = %+v, want not declared", got)
+				}
+				return
+			}
+			if !ok || !reflect.DeepEqual(got, *tt.want) {
+				t.Errorf("DecodeEvent() = %#v, %v\nwant %#v", got, ok, *tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeInvocation(t *testing.T) {
+	spec, err := ParseContractSpec(specStream(t, escrowSpec()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	milestone := mapVal("amount", u64Val(500), "description", strVal("design"), "status", u32Val(2))
+	got, ok := spec.DecodeInvocation("release", []xdr.ScVal{u64Val(7), milestone, {Type: xdr.ScValTypeScvVoid}})
+	want := map[string]any{
+		"escrow_id": uint64(7),
+		"milestone": map[string]any{"amount": uint64(500), "description": "design", "status": "Released"},
+		"memo":      nil,
+	}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeInvocation() = %#v, %v, want %#v", got, ok, want)
+	}
+
+	if _, ok := spec.DecodeInvocation("withdraw", nil); ok {
+		t.Error("undeclared function decoded")
+	}
+}
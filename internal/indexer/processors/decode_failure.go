@@ -0,0 +1,12 @@
+package processors
+
+import (
+	"context"
+
+	"indexer/internal/indexer/types"
+)
+
+// DecodeFailureRecorder recibe los eventos que un procesador no pudo decodificar
+type DecodeFailureRecorder interface {
+	RecordDecodeFailure(ctx context.Context, failure types.DecodeFailure)
+}
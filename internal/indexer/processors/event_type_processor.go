@@ -0,0 +1,213 @@
+package processors
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// EventExample is a sample event payload of a given type
+type EventExample struct {
+	ContractID     string             `json:"contract_id"`
+	LedgerSequence uint32             `json:"ledger_sequence"`
+	LedgerClosedAt time.Time          `json:"ledger_closed_at"`
+	TxHash         string             `json:"tx_hash"`
+	Topics         []interface{}      `json:"topics"`
+	Data           interface{}        `json:"data"`
+	Decoded        *DecodedEvent      `json:"decoded,omitempty"`    // Named fields, when the contract's spec is loaded
+	Invocation     *DecodedInvocation `json:"invocation,omitempty"` // Call that emitted the event, when the invoked contract's spec is loaded
+}
+
+// EventTypeStats aggregates what has been observed for one event type
+type EventTypeStats struct {
+	Type            string       `json:"type"`
+	Count           uint64       `json:"count"`
+	FirstSeenLedger uint32       `json:"first_seen_ledger"`
+	FirstSeenAt     time.Time    `json:"first_seen_at"` // Close time of FirstSeenLedger
+	LastSeenLedger  uint32       `json:"last_seen_ledger"`
+	LastSeenAt      time.Time    `json:"last_seen_at"` // Close time of LastSeenLedger
+	Example         EventExample `json:"example"`
+}
+
+// EventTypeProcessor keeps a registry of every contract event type observed, keyed by its first topic
+type EventTypeProcessor struct {
+	specs       *ContractSpecs
+	mu          sync.RWMutex
+	types       map[string]*EventTypeStats
+	lastUpdated time.Time // Close time of the most recent ledger processed
+}
+
+// NewEventTypeProcessor creates an empty event type registry; example payloads are decoded with specs (can be nil)
+func NewEventTypeProcessor(specs *ContractSpecs) *EventTypeProcessor {
+	return &EventTypeProcessor{
+		specs: specs,
+		types: make(map[string]*EventTypeStats),
+	}
+}
+
+func (p *EventTypeProcessor) Name() string {
+	return "EventTypeProcessor"
+}
+
+// ProcessLedger tracks the close time of the newest ledger seen
+func (p *EventTypeProcessor) ProcessLedger(ctx context.Context, ledger xdr.LedgerCloseMeta) error {
+	closeTime := time.Unix(ledger.LedgerCloseTime(), 0).UTC()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if closeTime.After(p.lastUpdated) {
+		p.lastUpdated = closeTime
+	}
+
+	return nil
+}
+
+// ProcessTransaction records the type of every contract event in the transaction
+func (p *EventTypeProcessor) ProcessTransaction(ctx context.Context, tx ingest.LedgerTransaction) error {
+	events, err := tx.GetContractEvents()
+	if err != nil {
+		// Not a Soroban transaction
+		return nil
+	}
+
+	ledgerSeq := tx.Ledger.LedgerSequence()
+	closeTime := time.Unix(tx.Ledger.LedgerCloseTime(), 0).UTC()
+	txHash := hex.EncodeToString(tx.Result.TransactionHash[:])
+	invocation := p.decodeInvocation(tx)
+
+	for _, event := range events {
+		if event.Type != xdr.ContractEventTypeContract {
+			continue
+		}
+		p.record(event, ledgerSeq, closeTime, txHash, invocation)
+	}
+
+	return nil
+}
+
+// record updates the stats of the event's type
+func (p *EventTypeProcessor) record(event xdr.ContractEvent, ledgerSeq uint32, closeTime time.Time, txHash string, invocation *DecodedInvocation) {
+	body, ok := event.Body.GetV0()
+	if !ok {
+		return
+	}
+
+	eventType := "unknown"
+	if len(body.Topics) > 0 {
+		if sym, ok := body.Topics[0].GetSym(); ok {
+			eventType = string(sym)
+		} else if str, ok := body.Topics[0].GetStr(); ok {
+			eventType = string(str)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats, exists := p.types[eventType]
+	if !exists {
+		topics := make([]interface{}, 0, len(body.Topics))
+		for _, topic := range body.Topics {
+			topics = append(topics, scValToInterface(topic))
+		}
+
+		contractID := contractIDString(event)
+		stats = &EventTypeStats{
+			Type:            eventType,
+			FirstSeenLedger: ledgerSeq,
+			FirstSeenAt:     closeTime,
+			Example: EventExample{
+				ContractID:     contractID,
+				LedgerSequence: ledgerSeq,
+				LedgerClosedAt: closeTime,
+				TxHash:         txHash,
+				Topics:         topics,
+				Data:           scValToInterface(body.Data),
+				Invocation:     invocation,
+			},
+		}
+		if spec, ok := p.specs.Lookup(contractID); ok {
+			if decoded, ok := spec.DecodeEvent(body.Topics, body.Data); ok {
+				stats.Example.Decoded = &decoded
+			}
+		}
+		p.types[eventType] = stats
+	}
+
+	stats.Count++
+	if ledgerSeq < stats.FirstSeenLedger {
+		stats.FirstSeenLedger = ledgerSeq
+		stats.FirstSeenAt = closeTime
+	}
+	if ledgerSeq > stats.LastSeenLedger {
+		stats.LastSeenLedger = ledgerSeq
+		stats.LastSeenAt = closeTime
+	}
+}
+
+// decodeInvocation decodes the contract call of a Soroban transaction when the invoked contract's spec is loaded
+func (p *EventTypeProcessor) decodeInvocation(tx ingest.LedgerTransaction) *DecodedInvocation {
+	if p.specs == nil {
+		return nil
+	}
+
+	// Soroban transactions carry exactly one operation
+	ops := tx.Envelope.Operations()
+	if len(ops) != 1 {
+		return nil
+	}
+	op, ok := ops[0].Body.GetInvokeHostFunctionOp()
+	if !ok {
+		return nil
+	}
+	call, ok := op.HostFunction.GetInvokeContract()
+	if !ok {
+		return nil
+	}
+
+	contractID, err := call.ContractAddress.String()
+	if err != nil {
+		return nil
+	}
+	spec, ok := p.specs.Lookup(contractID)
+	if !ok {
+		return nil
+	}
+
+	args, ok := spec.DecodeInvocation(string(call.FunctionName), call.Args)
+	if !ok {
+		return nil
+	}
+	return &DecodedInvocation{ContractID: contractID, Function: string(call.FunctionName), Args: args}
+}
+
+// EventTypes returns the stats of every observed event type, sorted by type
+func (p *EventTypeProcessor) EventTypes() []EventTypeStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	list := make([]EventTypeStats, 0, len(p.types))
+	for _, stats := range p.types {
+		list = append(list, *stats)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Type < list[j].Type
+	})
+
+	return list
+}
+
+// LastUpdated returns the close time of the newest ledger reflected in the registry
+func (p *EventTypeProcessor) LastUpdated() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.lastUpdated
+}
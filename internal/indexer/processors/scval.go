@@ -0,0 +1,110 @@
+package processors
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// scValToInterface converts a ScVal into plain Go values suitable for JSON encoding
+func scValToInterface(val xdr.ScVal) interface{} {
+	switch val.Type {
+	case xdr.ScValTypeScvVoid:
+		return nil
+	case xdr.ScValTypeScvBool:
+		b, _ := val.GetB()
+		return b
+	case xdr.ScValTypeScvU32:
+		v, _ := val.GetU32()
+		return uint32(v)
+	case xdr.ScValTypeScvI32:
+		v, _ := val.GetI32()
+		return int32(v)
+	case xdr.ScValTypeScvU64:
+		v, _ := val.GetU64()
+		return uint64(v)
+	case xdr.ScValTypeScvI64:
+		v, _ := val.GetI64()
+		return int64(v)
+	case xdr.ScValTypeScvTimepoint:
+		v, _ := val.GetTimepoint()
+		return uint64(v)
+	case xdr.ScValTypeScvDuration:
+		v, _ := val.GetDuration()
+		return uint64(v)
+	case xdr.ScValTypeScvU128:
+		parts, _ := val.GetU128()
+		return int128ToString(uint64(parts.Hi), uint64(parts.Lo), false)
+	case xdr.ScValTypeScvI128:
+		parts, _ := val.GetI128()
+		return int128ToString(uint64(parts.Hi), uint64(parts.Lo), int64(parts.Hi) < 0)
+	case xdr.ScValTypeScvBytes:
+		b, _ := val.GetBytes()
+		return hex.EncodeToString(b)
+	case xdr.ScValTypeScvString:
+		s, _ := val.GetStr()
+		return string(s)
+	case xdr.ScValTypeScvSymbol:
+		s, _ := val.GetSym()
+		return string(s)
+	case xdr.ScValTypeScvAddress:
+		addr, _ := val.GetAddress()
+		encoded, err := addr.String()
+		if err != nil {
+			return nil
+		}
+		return encoded
+	case xdr.ScValTypeScvVec:
+		vec, ok := val.GetVec()
+		if !ok || vec == nil {
+			return []interface{}{}
+		}
+		items := make([]interface{}, 0, len(*vec))
+		for _, item := range *vec {
+			items = append(items, scValToInterface(item))
+		}
+		return items
+	case xdr.ScValTypeScvMap:
+		scMap, ok := val.GetMap()
+		if !ok || scMap == nil {
+			return map[string]interface{}{}
+		}
+		entries := make(map[string]interface{}, len(*scMap))
+		for _, entry := range *scMap {
+			entries[fmt.Sprint(scValToInterface(entry.Key))] = scValToInterface(entry.Val)
+		}
+		return entries
+	default:
+		return val.Type.String()
+	}
+}
+
+// int128ToString renders the two's complement hi/lo parts as a decimal string
+func int128ToString(hi, lo uint64, negative bool) string {
+	value := new(big.Int).SetUint64(hi)
+	value.Lsh(value, 64)
+	value.Or(value, new(big.Int).SetUint64(lo))
+
+	if negative {
+		// Subtract 2^128 to get the signed value
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+
+	return value.String()
+}
+
+// contractIDString encodes the event's contract ID as a C... strkey
+func contractIDString(event xdr.ContractEvent) string {
+	if event.ContractId == nil {
+		return ""
+	}
+
+	encoded, err := strkey.Encode(strkey.VersionByteContract, event.ContractId[:])
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"time"
 
 	"indexer/internal/indexer/types"
+	"indexer/internal/metrics"
 
 	"github.com/stellar/go/ingest"
 	"github.com/stellar/go/strkey"
@@ -19,14 +21,16 @@ type USDCTransferProcessor struct {
 	contractAddress string
 	assetString     string
 	buffer          chan types.USDCTransferEvent
+	failures        DecodeFailureRecorder
 }
 
-// NewUSDCTransferProcessor crea un nuevo procesador USDC
-func NewUSDCTransferProcessor() *USDCTransferProcessor {
+// NewUSDCTransferProcessor crea un nuevo procesador USDC; los eventos que no se puedan decodificar se envían a failures (puede ser nil)
+func NewUSDCTransferProcessor(failures DecodeFailureRecorder) *USDCTransferProcessor {
 	return &USDCTransferProcessor{
 		// USDC mainnet - ajustar para testnet si es necesario
 		assetString: "USDC:GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN",
 		buffer:      make(chan types.USDCTransferEvent, 1000), // Buffer de eventos
+		failures:    failures,
 	}
 }
 
@@ -51,13 +55,15 @@ func (p *USDCTransferProcessor) ProcessTransaction(ctx context.Context, tx inges
 	// Obtener hash de la transacción
 	txHash := hex.EncodeToString(tx.Result.TransactionHash[:])
 
-	// Obtener ledger sequence
+	// Obtener ledger sequence y hora de cierre
 	ledgerSeq := tx.Ledger.LedgerSequence()
+	closeTime := time.Unix(tx.Ledger.LedgerCloseTime(), 0).UTC()
 
 	// Iterar sobre eventos Soroban
-	for _, event := range tx.UnsafeMeta.V3.SorobanMeta.Events {
-		if err := p.processEvent(ctx, event, ledgerSeq, txHash); err != nil {
+	for i, event := range tx.UnsafeMeta.V3.SorobanMeta.Events {
+		if err := p.processEvent(ctx, event, ledgerSeq, closeTime, txHash); err != nil {
 			log.Printf("Error procesando evento: %v", err)
+			p.quarantine(ctx, event, ledgerSeq, closeTime, txHash, i, err)
 			// Continuar con otros eventos
 		}
 	}
@@ -66,7 +72,7 @@ func (p *USDCTransferProcessor) ProcessTransaction(ctx context.Context, tx inges
 }
 
 // processEvent procesa un evento individual
-func (p *USDCTransferProcessor) processEvent(ctx context.Context, event xdr.ContractEvent, ledgerSeq uint32, txHash string) error {
+func (p *USDCTransferProcessor) processEvent(ctx context.Context, event xdr.ContractEvent, ledgerSeq uint32, closeTime time.Time, txHash string) error {
 	// Solo procesar eventos de contrato
 	if event.Type != xdr.ContractEventTypeContract {
 		return nil
@@ -112,10 +118,11 @@ func (p *USDCTransferProcessor) processEvent(ctx context.Context, event xdr.Cont
 	// Crear evento
 	transferEvent := types.USDCTransferEvent{
 		Event: types.Event{
-			LedgerSequence: ledgerSeq,
-			TxHash:         txHash,
-			Type:           "transfer",
-			ContractID:     p.contractAddress,
+			LedgerSequence:  ledgerSeq,
+			LedgerCloseTime: closeTime,
+			TxHash:          txHash,
+			Type:            "transfer",
+			ContractID:      p.contractAddress,
 		},
 		From:   from,
 		To:     to,
@@ -125,15 +132,51 @@ func (p *USDCTransferProcessor) processEvent(ctx context.Context, event xdr.Cont
 	// Enviar al buffer (non-blocking)
 	select {
 	case p.buffer <- transferEvent:
+		metrics.EventsEmitted.WithLabelValues(p.Name(), transferEvent.Type).Inc()
 		log.Printf("🔄 USDC Transfer: %s -> %s: %s USDC (Ledger: %d, Tx: %s)",
 			from, to, p.formatUSDC(amount), ledgerSeq, txHash[:8])
 	default:
+		metrics.EventsDropped.WithLabelValues(p.Name()).Inc()
 		log.Printf("⚠️  Buffer lleno, descartando evento")
 	}
 
 	return nil
 }
 
+// quarantine guarda el XDR del evento que falló para poder re-decodificarlo más tarde
+func (p *USDCTransferProcessor) quarantine(ctx context.Context, event xdr.ContractEvent, ledgerSeq uint32, closeTime time.Time, txHash string, index int, decodeErr error) {
+	if p.failures == nil {
+		return
+	}
+
+	raw, err := xdr.MarshalBase64(event)
+	if err != nil {
+		log.Printf("⚠️  No se pudo serializar el evento fallido: %v", err)
+		return
+	}
+
+	p.failures.RecordDecodeFailure(ctx, types.DecodeFailure{
+		Processor:      p.Name(),
+		LedgerSequence: ledgerSeq,
+		LedgerClosedAt: closeTime,
+		TxHash:         txHash,
+		EventIndex:     index,
+		RawXDR:         raw,
+		Error:          decodeErr.Error(),
+		CreatedAt:      time.Now().UTC(),
+	})
+}
+
+// Redecode vuelve a procesar un evento en cuarentena
+func (p *USDCTransferProcessor) Redecode(ctx context.Context, failure types.DecodeFailure) error {
+	var event xdr.ContractEvent
+	if err := xdr.SafeUnmarshalBase64(failure.RawXDR, &event); err != nil {
+		return fmt.Errorf("XDR inválido: %w", err)
+	}
+
+	return p.processEvent(ctx, event, failure.LedgerSequence, failure.LedgerClosedAt, failure.TxHash)
+}
+
 // addressFromScVal convierte ScVal a dirección string
 func (p *USDCTransferProcessor) addressFromScVal(val xdr.ScVal) (string, error) {
 	addr, ok := val.GetAddress()
@@ -190,6 +233,22 @@ func (p *USDCTransferProcessor) formatUSDC(amount string) string {
 	return result.Text('f', 2) // 2 decimales para display
 }
 
+// Flush espera a que el consumidor vacíe el buffer, para no perder eventos al apagar
+func (p *USDCTransferProcessor) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for len(p.buffer) > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("%d eventos sin consumir: %w", len(p.buffer), ctx.Err())
+		}
+	}
+
+	return nil
+}
+
 // GetBuffer retorna el canal de buffer para consumir eventos
 func (p *USDCTransferProcessor) GetBuffer() <-chan types.USDCTransferEvent {
 	return p.buffer
@@ -0,0 +1,99 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"indexer/internal/storage"
+)
+
+// Files in the checkpoint directory holding the live lane and its downstream data
+const (
+	liveCheckpointFile     = "live"
+	liveHistoryFile        = "live_history.json"
+	decodeFailuresFile     = "decode_failures.json"
+	failedTransactionsFile = "failed_transactions.json"
+)
+
+// downstreamStore is data written while ingesting, counted in each checkpoint and truncated on resume
+type downstreamStore interface {
+	storage.RowCounter
+	storage.LedgerTruncater
+}
+
+// newLiveCheckpoints returns the live checkpoint store, recording row counts of decode failures and failed transactions
+func newLiveCheckpoints(dir string, decodeFailures, failedTxs downstreamStore) *storage.FileCheckpointHistory {
+	return storage.NewFileCheckpointHistory(
+		storage.NewFileCheckpointStore(filepath.Join(dir, liveCheckpointFile)),
+		filepath.Join(dir, liveHistoryFile),
+		map[string]storage.RowCounter{
+			"decode_failures":     decodeFailures,
+			"failed_transactions": failedTxs,
+		},
+	)
+}
+
+// LiveCheckpoint returns the last ledger saved by the live lane, 0 if none
+func LiveCheckpoint(ctx context.Context, checkpointDir string) (uint32, error) {
+	return storage.NewFileCheckpointStore(filepath.Join(checkpointDir, liveCheckpointFile)).Load(ctx)
+}
+
+// ResumeResult reports what ResumeAt rewound
+type ResumeResult struct {
+	Ledger   uint32                    // New live checkpoint
+	Previous uint32                    // Live checkpoint before the rewind
+	Record   *storage.CheckpointRecord // Latest history record at or before Ledger, nil if none
+	Removed  map[string]int            // Rows deleted per downstream store
+}
+
+// ResumeAt rewinds the live lane to ledger: downstream data of later ledgers is deleted and
+// ledger becomes the live checkpoint, so the next run starts at ledger+1. Must not run while the indexer does.
+func ResumeAt(ctx context.Context, checkpointDir string, ledger uint32) (ResumeResult, error) {
+	decodeFailures := storage.NewFileDecodeFailureStore(filepath.Join(checkpointDir, decodeFailuresFile))
+	failedTxs := storage.NewFileFailedTransactionStore(filepath.Join(checkpointDir, failedTransactionsFile))
+	checkpoints := newLiveCheckpoints(checkpointDir, decodeFailures, failedTxs)
+
+	previous, err := checkpoints.Load(ctx)
+	if err != nil {
+		return ResumeResult{}, err
+	}
+	if ledger > previous {
+		return ResumeResult{}, fmt.Errorf("ledger %d is ahead of the live checkpoint %d", ledger, previous)
+	}
+
+	result := ResumeResult{Ledger: ledger, Previous: previous, Removed: make(map[string]int)}
+
+	records, err := checkpoints.List(ctx)
+	if err != nil {
+		return ResumeResult{}, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Ledger <= ledger {
+			result.Record = &records[i]
+			break
+		}
+	}
+
+	stores := map[string]storage.LedgerTruncater{
+		"decode_failures":     decodeFailures,
+		"failed_transactions": failedTxs,
+		"checkpoint_history":  checkpoints,
+	}
+	for name, store := range stores {
+		removed, err := store.TruncateAfter(ctx, ledger)
+		if err != nil {
+			return ResumeResult{}, fmt.Errorf("error truncating %s: %w", name, err)
+		}
+		result.Removed[name] = removed
+	}
+
+	// Saved last: if truncation fails halfway, the run can be repeated
+	if err := checkpoints.Save(ctx, ledger); err != nil {
+		return ResumeResult{}, err
+	}
+
+	log.Printf("⏪ Live checkpoint rewound from ledger %d to %d", previous, ledger)
+	return result, nil
+}
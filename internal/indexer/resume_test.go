@@ -0,0 +1,113 @@
+package indexer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"indexer/internal/indexer/types"
+	"indexer/internal/service/ingest"
+	"indexer/internal/storage"
+)
+
+// seedCheckpointDir writes a live lane that reached ledger 110, with downstream rows
+// and checkpoints spread over ledgers 100 to 110
+func seedCheckpointDir(t *testing.T) string {
+	t.Helper()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	decodeFailures := storage.NewFileDecodeFailureStore(filepath.Join(dir, decodeFailuresFile))
+	failedTxs := storage.NewFileFailedTransactionStore(filepath.Join(dir, failedTransactionsFile))
+	checkpoints := newLiveCheckpoints(dir, decodeFailures, failedTxs)
+
+	for _, ledger := range []uint32{100, 105, 110} {
+		if err := decodeFailures.SaveDecodeFailure(ctx, types.DecodeFailure{LedgerSequence: ledger}); err != nil {
+			t.Fatal(err)
+		}
+		if err := failedTxs.SaveFailedTransaction(ctx, ingest.FailedTransaction{LedgerSequence: ledger + 2}); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkpoints.Save(ctx, ledger); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestResumeAt(t *testing.T) {
+	ctx := context.Background()
+	dir := seedCheckpointDir(t)
+
+	result, err := ResumeAt(ctx, dir, 105)
+	if err != nil {
+		t.Fatalf("ResumeAt() error = %v", err)
+	}
+
+	if result.Ledger != 105 || result.Previous != 110 {
+		t.Errorf("ResumeAt() moved %d -> %d, want 110 -> 105", result.Previous, result.Ledger)
+	}
+	if result.Record == nil || result.Record.Ledger != 105 || result.Record.Rows["decode_failures"] != 2 {
+		t.Errorf("Record = %+v, want the checkpoint of ledger 105 with 2 decode failures", result.Record)
+	}
+
+	// Decode failure 110, failed transaction 107 and 112 and checkpoint 110 are past ledger 105
+	want := map[string]int{"decode_failures": 1, "failed_transactions": 2, "checkpoint_history": 1}
+	for name, rows := range want {
+		if result.Removed[name] != rows {
+			t.Errorf("Removed[%s] = %d, want %d", name, result.Removed[name], rows)
+		}
+	}
+
+	if ledger, err := LiveCheckpoint(ctx, dir); err != nil || ledger != 105 {
+		t.Errorf("LiveCheckpoint() = %d, %v, want 105", ledger, err)
+	}
+
+	failures, err := storage.NewFileDecodeFailureStore(filepath.Join(dir, decodeFailuresFile)).ListDecodeFailures(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, failure := range failures {
+		if failure.LedgerSequence > 105 {
+			t.Errorf("decode failure of ledger %d kept", failure.LedgerSequence)
+		}
+	}
+
+	// The rewound checkpoint is recorded, so the history ends at the new position
+	records, err := newLiveCheckpoints(dir, nil, nil).List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[len(records)-1].Ledger != 105 {
+		t.Errorf("history = %+v, want 100, 105 and the rewound 105", records)
+	}
+}
+
+func TestResumeAtBeforeHistory(t *testing.T) {
+	dir := seedCheckpointDir(t)
+
+	result, err := ResumeAt(context.Background(), dir, 50)
+	if err != nil {
+		t.Fatalf("ResumeAt() error = %v", err)
+	}
+	if result.Record != nil {
+		t.Errorf("Record = %+v, want none before the first checkpoint", result.Record)
+	}
+	if result.Removed["decode_failures"] != 3 || result.Removed["failed_transactions"] != 3 {
+		t.Errorf("Removed = %v, want every downstream row", result.Removed)
+	}
+}
+
+func TestResumeAtAheadOfCheckpoint(t *testing.T) {
+	dir := seedCheckpointDir(t)
+
+	if _, err := ResumeAt(context.Background(), dir, 111); err == nil {
+		t.Fatal("ResumeAt() past the live checkpoint succeeded")
+	}
+
+	// Nothing was touched
+	if ledger, err := LiveCheckpoint(context.Background(), dir); err != nil || ledger != 110 {
+		t.Errorf("LiveCheckpoint() = %d, %v, want 110", ledger, err)
+	}
+}
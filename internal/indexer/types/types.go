@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"time"
 
 	"github.com/stellar/go/ingest"
 	"github.com/stellar/go/xdr"
@@ -28,11 +29,12 @@ type CheckpointStore interface {
 
 // Event representa un evento genérico procesado
 type Event struct {
-	LedgerSequence uint32
-	TxHash         string
-	Type           string
-	ContractID     string
-	Data           map[string]interface{}
+	LedgerSequence  uint32
+	LedgerCloseTime time.Time
+	TxHash          string
+	Type            string
+	ContractID      string
+	Data            map[string]interface{}
 }
 
 // USDCTransferEvent representa específicamente una transferencia USDC
@@ -42,3 +44,24 @@ type USDCTransferEvent struct {
 	To     string
 	Amount string // Como string para evitar problemas de precisión
 }
+
+// DecodeFailure registra un evento que no pudo decodificarse, con su XDR original para reintentar
+type DecodeFailure struct {
+	ID             string     `json:"id"`
+	Processor      string     `json:"processor"`
+	LedgerSequence uint32     `json:"ledger_sequence"`
+	LedgerClosedAt time.Time  `json:"ledger_closed_at"`
+	TxHash         string     `json:"tx_hash"`
+	EventIndex     int        `json:"event_index"`
+	RawXDR         string     `json:"raw_xdr"` // ContractEvent en base64
+	Error          string     `json:"error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// DecodeFailureStore persiste los eventos en cuarentena
+type DecodeFailureStore interface {
+	SaveDecodeFailure(ctx context.Context, failure DecodeFailure) error
+	ListDecodeFailures(ctx context.Context, unresolvedOnly bool) ([]DecodeFailure, error)
+	MarkDecodeFailureResolved(ctx context.Context, id string) error
+}
@@ -0,0 +1,102 @@
+package captivecore_backend
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/network"
+)
+
+// LedgerBuilder is responsible for constructing captive stellar-core ledger backend instances
+type LedgerBuilder struct {
+	ClientConfig ClientConfig
+}
+
+// Build creates a new captive core backend. The stellar-core process is only launched on PrepareRange.
+func (lb *LedgerBuilder) Build() (*ledgerbackend.CaptiveStellarCore, error) {
+	config := lb.ClientConfig
+
+	// Validate that a binary is provided
+	if config.BinaryPath == "" {
+		return nil, fmt.Errorf("ClientConfig.BinaryPath value is empty, please provide the path to stellar-core")
+	}
+
+	archiveURLs, err := lb.HistoryArchiveURLs()
+	if err != nil {
+		return nil, err
+	}
+
+	toml, err := lb.newCoreToml(archiveURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := ledgerbackend.NewCaptive(ledgerbackend.CaptiveCoreConfig{
+		BinaryPath:          config.BinaryPath,
+		NetworkPassphrase:   config.NetworkPassphrase,
+		HistoryArchiveURLs:  archiveURLs,
+		Toml:                toml,
+		StoragePath:         config.StoragePath,
+		CheckpointFrequency: historyarchive.DefaultCheckpointFrequency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating captive core backend: %w", err)
+	}
+
+	return backend, nil
+}
+
+// newCoreToml loads the configured TOML file or generates one from the network defaults
+func (lb *LedgerBuilder) newCoreToml(archiveURLs []string) (*ledgerbackend.CaptiveCoreToml, error) {
+	config := lb.ClientConfig
+
+	params := ledgerbackend.CaptiveCoreTomlParams{
+		NetworkPassphrase:  config.NetworkPassphrase,
+		HistoryArchiveURLs: archiveURLs,
+		CoreBinaryPath:     config.BinaryPath,
+	}
+
+	if config.ConfigPath != "" {
+		toml, err := ledgerbackend.NewCaptiveCoreTomlFromFile(config.ConfigPath, params)
+		if err != nil {
+			return nil, fmt.Errorf("error loading captive core config %s: %w", config.ConfigPath, err)
+		}
+		return toml, nil
+	}
+
+	var defaults []byte
+	switch config.NetworkPassphrase {
+	case network.PublicNetworkPassphrase:
+		defaults = ledgerbackend.PubnetDefaultConfig
+	case network.TestNetworkPassphrase:
+		defaults = ledgerbackend.TestnetDefaultConfig
+	default:
+		return nil, fmt.Errorf("no default captive core config for network %q, please provide a config file", config.NetworkPassphrase)
+	}
+
+	toml, err := ledgerbackend.NewCaptiveCoreTomlFromData(defaults, params)
+	if err != nil {
+		return nil, fmt.Errorf("error generating captive core config: %w", err)
+	}
+
+	return toml, nil
+}
+
+// HistoryArchiveURLs returns the configured archives or the SDF archives of the network
+func (lb *LedgerBuilder) HistoryArchiveURLs() ([]string, error) {
+	config := lb.ClientConfig
+
+	if len(config.HistoryArchiveURLs) > 0 {
+		return config.HistoryArchiveURLs, nil
+	}
+
+	switch config.NetworkPassphrase {
+	case network.PublicNetworkPassphrase:
+		return network.PublicNetworkhistoryArchiveURLs, nil
+	case network.TestNetworkPassphrase:
+		return network.TestNetworkhistoryArchiveURLs, nil
+	default:
+		return nil, fmt.Errorf("no default history archives for network %q, please provide HistoryArchiveURLs", config.NetworkPassphrase)
+	}
+}
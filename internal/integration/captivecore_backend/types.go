@@ -0,0 +1,10 @@
+package captivecore_backend
+
+// ClientConfig contains the configuration of a captive stellar-core ledger backend
+type ClientConfig struct {
+	BinaryPath         string   // Path to the stellar-core binary
+	ConfigPath         string   // Captive core TOML file (empty = generated from the network defaults)
+	StoragePath        string   // Directory where captive core keeps its buckets (empty = temp dir)
+	NetworkPassphrase  string   // Network the core instance joins
+	HistoryArchiveURLs []string // History archives to catch up from (empty = network defaults)
+}
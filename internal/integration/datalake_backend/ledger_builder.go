@@ -0,0 +1,134 @@
+package datalake_backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/support/datastore"
+)
+
+// LedgerBuilder is responsible for constructing buffered storage ledger backend instances
+type LedgerBuilder struct {
+	ClientConfig ClientConfig
+}
+
+// Build creates a new buffered storage backend reading from the configured data lake
+func (lb *LedgerBuilder) Build() (*ledgerbackend.BufferedStorageBackend, error) {
+	ctx := context.Background()
+
+	dataStoreConfig, err := lb.newDataStoreConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	dataStore, err := datastore.NewDataStore(ctx, dataStoreConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating datastore: %w", err)
+	}
+
+	schema, err := lb.loadSchema(ctx, dataStore, dataStoreConfig)
+	if err != nil {
+		dataStore.Close()
+		return nil, fmt.Errorf("error loading datastore schema: %w", err)
+	}
+
+	backendConfig := lb.newBackendConfig()
+	if backendConfig.NumWorkers > backendConfig.BufferSize {
+		dataStore.Close()
+		return nil, fmt.Errorf("%d download workers exceed the buffer of %d files", backendConfig.NumWorkers, backendConfig.BufferSize)
+	}
+
+	backend, err := ledgerbackend.NewBufferedStorageBackend(backendConfig, dataStore, schema)
+	if err != nil {
+		dataStore.Close()
+		return nil, fmt.Errorf("error creating buffered storage backend: %w", err)
+	}
+
+	return backend, nil
+}
+
+// newDataStoreConfig maps the client configuration to the datastore parameters
+func (lb *LedgerBuilder) newDataStoreConfig() (datastore.DataStoreConfig, error) {
+	config := lb.ClientConfig.DataStore
+
+	// Validate that a bucket is provided
+	if config.BucketPath == "" {
+		return datastore.DataStoreConfig{}, fmt.Errorf("DataStoreConfig.BucketPath value is empty, please provide a valid bucket path")
+	}
+
+	params := map[string]string{
+		"destination_bucket_path": config.BucketPath,
+	}
+
+	storageType := strings.ToUpper(config.Type)
+	switch storageType {
+	case "GCS":
+	case "S3":
+		if config.Region != "" {
+			params["region"] = config.Region
+		}
+		if config.Endpoint != "" {
+			params["endpoint_url"] = config.Endpoint
+		}
+	default:
+		return datastore.DataStoreConfig{}, fmt.Errorf("unsupported datastore type %q, expected GCS or S3", config.Type)
+	}
+
+	return datastore.DataStoreConfig{
+		Type:   storageType,
+		Params: params,
+		Schema: datastore.DataStoreSchema{
+			LedgersPerFile:    config.LedgersPerFile,
+			FilesPerPartition: config.FilesPerPartition,
+		},
+	}, nil
+}
+
+// loadSchema reads the file layout from the bucket manifest Galexie publishes. Without a manifest
+// the configured layout is used, and unset values fall back to Galexie's defaults.
+func (lb *LedgerBuilder) loadSchema(ctx context.Context, dataStore datastore.DataStore, config datastore.DataStoreConfig) (datastore.DataStoreSchema, error) {
+	schema, err := datastore.LoadSchema(ctx, dataStore, config)
+	if err == nil || (config.Schema.LedgersPerFile > 0 && config.Schema.FilesPerPartition > 0) {
+		return schema, err
+	}
+
+	// LoadSchema only accepts a missing manifest when the layout is complete. Unset values are
+	// not compared against a manifest, so a readable one already succeeded above.
+	if config.Schema.LedgersPerFile == 0 {
+		config.Schema.LedgersPerFile = DefaultLedgersPerFile
+	}
+	if config.Schema.FilesPerPartition == 0 {
+		config.Schema.FilesPerPartition = DefaultFilesPerPartition
+	}
+	return datastore.LoadSchema(ctx, dataStore, config)
+}
+
+// newBackendConfig creates the buffering options, falling back to defaults for unset values
+func (lb *LedgerBuilder) newBackendConfig() ledgerbackend.BufferedStorageBackendConfig {
+	buffer := lb.ClientConfig.Buffer
+
+	config := ledgerbackend.BufferedStorageBackendConfig{
+		BufferSize: 100,
+		NumWorkers: 10,
+		RetryLimit: 3,
+		RetryWait:  5 * time.Second,
+	}
+
+	if buffer.BufferSize > 0 {
+		config.BufferSize = buffer.BufferSize
+	}
+	if buffer.NumWorkers > 0 {
+		config.NumWorkers = buffer.NumWorkers
+	}
+	if buffer.RetryLimit > 0 {
+		config.RetryLimit = buffer.RetryLimit
+	}
+	if buffer.RetryWait > 0 {
+		config.RetryWait = buffer.RetryWait
+	}
+
+	return config
+}
@@ -0,0 +1,35 @@
+package datalake_backend
+
+import "time"
+
+// Galexie's default file layout, used when the bucket has no manifest and none is configured
+const (
+	DefaultLedgersPerFile    = 1
+	DefaultFilesPerPartition = 64000
+)
+
+// DataStoreConfig contains the configuration for reading LedgerCloseMeta files from a Galexie data lake
+type DataStoreConfig struct {
+	Type       string // Storage type: "GCS" or "S3"
+	BucketPath string // Bucket and optional prefix holding the ledger files (e.g. "my-bucket/ledgers/pubnet")
+	Region     string // Bucket region (S3 only)
+	Endpoint   string // Custom endpoint URL (S3-compatible storage only)
+
+	// File layout, checked against the bucket manifest when there is one (0 = from the manifest)
+	LedgersPerFile    uint32
+	FilesPerPartition uint32
+}
+
+// BufferConfig holds the read-ahead settings of the buffered storage backend
+type BufferConfig struct {
+	BufferSize uint32        // Number of ledger files to buffer (default 100)
+	NumWorkers uint32        // Number of parallel file downloads, at most BufferSize (default 10)
+	RetryLimit uint32        // Number of retries per file
+	RetryWait  time.Duration // Wait between retries
+}
+
+// ClientConfig contains the full configuration of a data lake ledger backend
+type ClientConfig struct {
+	DataStore DataStoreConfig
+	Buffer    BufferConfig
+}
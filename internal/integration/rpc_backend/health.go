@@ -0,0 +1,18 @@
+package rpc_backend
+
+import "context"
+
+// Health is the result of the getHealth method
+type Health struct {
+	Status                string `json:"status"` // "healthy" when the server is in sync
+	LatestLedger          uint32 `json:"latestLedger"`
+	OldestLedger          uint32 `json:"oldestLedger"`
+	LedgerRetentionWindow uint32 `json:"ledgerRetentionWindow"`
+}
+
+// GetHealth asks the RPC server whether it is in sync with the network
+func (c ClientConfig) GetHealth(ctx context.Context) (Health, error) {
+	var health Health
+	err := c.call(ctx, "getHealth", nil, &health)
+	return health, err
+}
@@ -0,0 +1,62 @@
+package rpc_backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// jsonRPCResponse is the envelope of every JSON-RPC response
+type jsonRPCResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// call invokes a JSON-RPC method on the endpoint and decodes its result into result
+func (c ClientConfig) call(ctx context.Context, method string, params any, result any) error {
+	request := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+	}
+	if params != nil {
+		request["params"] = params
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := withStatusErrors(c.HTTPClient).Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("invalid %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+
+	if decoded.Error != nil {
+		return fmt.Errorf("%s error %d: %s", method, decoded.Error.Code, decoded.Error.Message)
+	}
+
+	if len(decoded.Result) == 0 || string(decoded.Result) == "null" {
+		return fmt.Errorf("empty %s response (status %d)", method, resp.StatusCode)
+	}
+
+	return json.Unmarshal(decoded.Result, result)
+}
@@ -2,7 +2,6 @@ package rpc_backend
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/stellar/go/ingest/ledgerbackend"
 )
@@ -28,7 +27,7 @@ func (lw *LedgerBuilder) newBackendOptions() (*ledgerbackend.RPCLedgerBackendOpt
 	return &ledgerbackend.RPCLedgerBackendOptions{
 		RPCServerURL: lw.ClientConfig.Endpoint,
 		BufferSize:   uint32(lw.ClientConfig.BufferSize),
-		HttpClient:   &http.Client{},
+		HttpClient:   withStatusErrors(lw.ClientConfig.HTTPClient),
 	}, nil
 }
 
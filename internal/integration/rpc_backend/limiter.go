@@ -0,0 +1,153 @@
+package rpc_backend
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"indexer/internal/metrics"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultThrottleWait is the pause after a 429 without Retry-After
+	defaultThrottleWait = time.Second
+
+	// Each 429 halves the rate down to minRateFraction of the maximum, each success recovers recoveryFraction of it
+	minRateFraction  = 0.05
+	recoveryFraction = 0.01
+)
+
+// RateLimit caps the requests sent to each RPC endpoint
+type RateLimit struct {
+	RequestsPerSecond float64 // Maximum sustained rate per endpoint (0 = unlimited, 429s still pause requests)
+	Burst             int     // Requests sent at once before the rate applies
+}
+
+// RateLimiter is an http.RoundTripper that throttles requests per endpoint host. The rate adapts to
+// the server: a 429 halves it and pauses every request to that host until Retry-After, and
+// successful requests raise it back towards the configured maximum.
+type RateLimiter struct {
+	base  http.RoundTripper
+	limit RateLimit
+	hosts map[string]*hostLimiter
+	mu    sync.Mutex
+}
+
+// hostLimiter is the adaptive token bucket of one host
+type hostLimiter struct {
+	bucket      *rate.Limiter
+	current     float64 // Current rate, 0 when unlimited
+	pausedUntil time.Time
+	mu          sync.Mutex
+}
+
+// NewRateLimiter wraps base with a per host limiter
+func NewRateLimiter(base http.RoundTripper, limit RateLimit) *RateLimiter {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	return &RateLimiter{base: base, limit: limit, hosts: make(map[string]*hostLimiter)}
+}
+
+// RoundTrip waits for the host's pause and token bucket, then sends the request
+func (l *RateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	limiter := l.host(host)
+	ctx := req.Context()
+
+	if wait := limiter.pause(); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := limiter.bucket.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := retryAfter(resp.Header.Get("Retry-After"))
+		if wait <= 0 {
+			wait = defaultThrottleWait
+		}
+		current := limiter.throttle(l.limit, wait)
+		metrics.RPCThrottled.WithLabelValues(host).Inc()
+		metrics.RPCRateLimit.WithLabelValues(host).Set(current)
+		log.Printf("🐢 RPC endpoint %s is rate limiting, pausing %s (rate %.1f req/s)", host, wait, current)
+	} else {
+		metrics.RPCRateLimit.WithLabelValues(host).Set(limiter.raise(l.limit))
+	}
+
+	return resp, nil
+}
+
+// host returns the limiter of a host, creating it at the maximum rate
+func (l *RateLimiter) host(host string) *hostLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.hosts[host]
+	if !ok {
+		limiter = &hostLimiter{bucket: rate.NewLimiter(rate.Inf, l.limit.Burst)}
+		if l.limit.RequestsPerSecond > 0 {
+			limiter.current = l.limit.RequestsPerSecond
+			limiter.bucket.SetLimit(rate.Limit(limiter.current))
+		}
+		l.hosts[host] = limiter
+	}
+
+	return limiter
+}
+
+// pause returns how long requests to the host must still wait after a 429
+func (h *hostLimiter) pause() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return time.Until(h.pausedUntil)
+}
+
+// throttle halves the rate and pauses the host for wait, returning the new rate
+func (h *hostLimiter) throttle(limit RateLimit, wait time.Duration) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if until := time.Now().Add(wait); until.After(h.pausedUntil) {
+		h.pausedUntil = until
+	}
+
+	if limit.RequestsPerSecond > 0 {
+		h.current = max(h.current/2, limit.RequestsPerSecond*minRateFraction)
+		h.bucket.SetLimit(rate.Limit(h.current))
+	}
+
+	return h.current
+}
+
+// raise raises the rate back towards the maximum after a successful request, returning the new rate
+func (h *hostLimiter) raise(limit RateLimit) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if limit.RequestsPerSecond > 0 && h.current < limit.RequestsPerSecond {
+		h.current = min(h.current+limit.RequestsPerSecond*recoveryFraction, limit.RequestsPerSecond)
+		h.bucket.SetLimit(rate.Limit(h.current))
+	}
+
+	return h.current
+}
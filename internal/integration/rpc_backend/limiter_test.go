@@ -0,0 +1,96 @@
+package rpc_backend
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value    string
+		min, max time.Duration
+	}{
+		{"", 0, 0},
+		{"5", 5 * time.Second, 5 * time.Second},
+		{"0", 0, 0},
+		{"-3", 0, 0},
+		{"soon", 0, 0},
+		{time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), 8 * time.Second, 10 * time.Second},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("retryAfter(%q) = %s, want between %s and %s", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
+
+// fixedResponses answers every request with status and header
+func fixedResponses(status int, header http.Header) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+}
+
+func roundTrip(t *testing.T, limiter *RateLimiter) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://rpc.test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimiterPausesForRetryAfter(t *testing.T) {
+	limit := RateLimit{RequestsPerSecond: 100, Burst: 10}
+	limiter := NewRateLimiter(fixedResponses(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}}), limit)
+
+	roundTrip(t, limiter)
+
+	host := limiter.host("rpc.test")
+	if wait := host.pause(); wait < 29*time.Second || wait > 30*time.Second {
+		t.Errorf("pause = %s, want the 30s of Retry-After", wait)
+	}
+	if host.current != 50 {
+		t.Errorf("rate = %v, want halved to 50", host.current)
+	}
+}
+
+func TestRateLimiterDefaultPause(t *testing.T) {
+	limiter := NewRateLimiter(fixedResponses(http.StatusTooManyRequests, nil), RateLimit{})
+
+	roundTrip(t, limiter)
+
+	if wait := limiter.host("rpc.test").pause(); wait <= 0 || wait > defaultThrottleWait {
+		t.Errorf("pause = %s, want up to %s", wait, defaultThrottleWait)
+	}
+}
+
+func TestRateLimiterAdaptsRate(t *testing.T) {
+	limit := RateLimit{RequestsPerSecond: 100, Burst: 10}
+	host := NewRateLimiter(nil, limit).host("rpc.test")
+
+	for range 20 {
+		host.throttle(limit, 0)
+	}
+	if want := limit.RequestsPerSecond * minRateFraction; host.current != want {
+		t.Errorf("rate after repeated 429s = %v, want the floor %v", host.current, want)
+	}
+
+	want := host.current + limit.RequestsPerSecond*recoveryFraction
+	if got := host.raise(limit); got != want {
+		t.Errorf("rate after a success = %v, want %v", got, want)
+	}
+	for range 200 {
+		host.raise(limit)
+	}
+	if host.current != limit.RequestsPerSecond {
+		t.Errorf("rate = %v, want capped at %v", host.current, limit.RequestsPerSecond)
+	}
+}
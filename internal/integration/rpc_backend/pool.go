@@ -0,0 +1,244 @@
+package rpc_backend
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"indexer/internal/metrics"
+)
+
+const (
+	// scoreDecay weighs the history of an endpoint against its latest result
+	scoreDecay = 0.8
+
+	// Failed endpoints are skipped for baseCooldown, doubled per consecutive failure up to maxCooldown
+	baseCooldown = 5 * time.Second
+	maxCooldown  = time.Minute
+)
+
+// Pool fails over between RPC endpoints. It is an http.RoundTripper: every request is sent to the
+// healthiest endpoint, and retried on the next one when it fails with a transport error, 429 or 5xx.
+// A 429 means the endpoint is busy, not broken: it only moves the endpoint back in the order until
+// Retry-After, without touching its score or health.
+type Pool struct {
+	base      http.RoundTripper
+	endpoints []*poolEndpoint
+	mu        sync.Mutex
+}
+
+// poolEndpoint is one RPC server and its health score
+type poolEndpoint struct {
+	url       *url.URL
+	label     string  // Host, used in logs and metrics so credentials in the URL are not exposed
+	score     float64 // 1 = every recent request succeeded, 0 = every one failed
+	failures  int     // Consecutive failures
+	downUntil time.Time
+
+	throttledUntil time.Time // Set by a 429, tried after the other endpoints until then
+}
+
+// EndpointStatus is the health of one endpoint of the pool
+type EndpointStatus struct {
+	Endpoint  string    `json:"endpoint"`
+	Score     float64   `json:"score"`
+	Healthy   bool      `json:"healthy"`
+	DownUntil time.Time `json:"down_until,omitzero"`
+}
+
+// SplitEndpoints parses a comma separated list of RPC URLs
+func SplitEndpoints(list string) []string {
+	var endpoints []string
+	for _, endpoint := range strings.Split(list, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// NewPool creates a pool over endpoints, in order of preference, sending requests through base
+func NewPool(endpoints []string, base http.RoundTripper) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoint configured")
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	pool := &Pool{base: base}
+	for _, endpoint := range endpoints {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid RPC endpoint %q", endpoint)
+		}
+
+		e := &poolEndpoint{url: parsed, label: parsed.Host, score: 1}
+		pool.endpoints = append(pool.endpoints, e)
+		metrics.RPCEndpointScore.WithLabelValues(e.label).Set(e.score)
+	}
+
+	return pool, nil
+}
+
+// Primary returns the preferred endpoint, the URL clients are configured with
+func (p *Pool) Primary() string {
+	return p.endpoints[0].url.String()
+}
+
+// RoundTrip sends the request to the endpoints in order of health until one answers
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	candidates := p.candidates()
+
+	for i, endpoint := range candidates {
+		last := i == len(candidates)-1
+
+		attempt, err := rewrite(req, endpoint.url, i > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := p.base.RoundTrip(attempt)
+
+		// The caller gave up, this says nothing about the endpoint
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			p.record(endpoint, nil)
+			return resp, nil
+		}
+
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			err = fmt.Errorf("status %d", resp.StatusCode)
+			p.recordThrottle(endpoint, retryAfter(resp.Header.Get("Retry-After")))
+		} else {
+			if err == nil {
+				err = fmt.Errorf("status %d", resp.StatusCode)
+			}
+			p.record(endpoint, err)
+		}
+
+		if last {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		log.Printf("🔀 RPC endpoint %s failed (%v), trying %s", endpoint.label, err, candidates[i+1].label)
+	}
+
+	return nil, fmt.Errorf("no RPC endpoint available")
+}
+
+// Status returns the health of every endpoint, in order of preference
+func (p *Pool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		status := EndpointStatus{Endpoint: e.label, Score: e.score, Healthy: !now.Before(e.downUntil)}
+		if !status.Healthy {
+			status.DownUntil = e.downUntil
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// candidates orders the endpoints: available ones by score then preference, then throttled ones,
+// cooling down ones last
+func (p *Pool) candidates() []*poolEndpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	rank := func(e *poolEndpoint) int {
+		switch {
+		case now.Before(e.downUntil):
+			return 2
+		case now.Before(e.throttledUntil):
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	ordered := append([]*poolEndpoint(nil), p.endpoints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if iRank, jRank := rank(ordered[i]), rank(ordered[j]); iRank != jRank {
+			return iRank < jRank
+		}
+		return ordered[i].score > ordered[j].score
+	})
+
+	return ordered
+}
+
+// record updates the score of an endpoint after a request
+func (p *Pool) record(e *poolEndpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := "ok"
+	if err == nil {
+		e.score = e.score*scoreDecay + (1 - scoreDecay)
+		e.failures = 0
+		e.downUntil = time.Time{}
+	} else {
+		result = "error"
+		e.score *= scoreDecay
+		e.failures++
+
+		cooldown := baseCooldown << min(e.failures-1, 4)
+		e.downUntil = time.Now().Add(min(cooldown, maxCooldown))
+	}
+
+	metrics.RPCEndpointRequests.WithLabelValues(e.label, result).Inc()
+	metrics.RPCEndpointScore.WithLabelValues(e.label).Set(e.score)
+}
+
+// recordThrottle moves an endpoint that answered 429 behind the others for wait (defaultThrottleWait if 0).
+// Its score and cooldown are left alone, since it is healthy, only busy.
+func (p *Pool) recordThrottle(e *poolEndpoint, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if wait <= 0 {
+		wait = defaultThrottleWait
+	}
+	if until := time.Now().Add(wait); until.After(e.throttledUntil) {
+		e.throttledUntil = until
+	}
+
+	metrics.RPCEndpointRequests.WithLabelValues(e.label, "throttled").Inc()
+}
+
+// rewrite points a copy of req at endpoint, rewinding the body when the request is sent again
+func rewrite(req *http.Request, endpoint *url.URL, resend bool) (*http.Request, error) {
+	attempt := req.Clone(req.Context())
+	attempt.URL = endpoint
+	attempt.Host = ""
+
+	if resend && req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("cannot resend request body to another RPC endpoint")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attempt.Body = body
+	}
+
+	return attempt, nil
+}
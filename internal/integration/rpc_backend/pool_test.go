@@ -0,0 +1,161 @@
+package rpc_backend
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc answers requests without a network
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// hostResponses answers each host with a fixed status, or an error when the status is 0, and
+// records the hosts in the order they were called
+func hostResponses(statuses map[string]int, header http.Header, calls *[]string) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*calls = append(*calls, req.URL.Host)
+
+		status := statuses[req.URL.Host]
+		if status == 0 {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+}
+
+func newTestPool(t *testing.T, base http.RoundTripper) *Pool {
+	t.Helper()
+	pool, err := NewPool([]string{"http://a", "http://b", "http://c"}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func send(t *testing.T, pool *Pool) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "http://a", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool.RoundTrip(req)
+}
+
+func hosts(endpoints []*poolEndpoint) string {
+	labels := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		labels = append(labels, e.label)
+	}
+	return strings.Join(labels, ",")
+}
+
+func TestPoolFailsOverOnErrors(t *testing.T) {
+	var calls []string
+	pool := newTestPool(t, hostResponses(map[string]int{"a": 0, "b": http.StatusBadGateway, "c": http.StatusOK}, nil, &calls))
+
+	resp, err := send(t, pool)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip = %v, %v, want 200 from c", resp, err)
+	}
+	if got := strings.Join(calls, ","); got != "a,b,c" {
+		t.Errorf("calls = %s, want a,b,c", got)
+	}
+
+	status := pool.Status()
+	if status[0].Healthy || status[1].Healthy || !status[2].Healthy {
+		t.Errorf("status = %+v, want a and b cooling down", status)
+	}
+	if status[0].Score >= 1 || status[2].Score != 1 {
+		t.Errorf("scores = %v, %v, want a lowered and c at 1", status[0].Score, status[2].Score)
+	}
+	if got := hosts(pool.candidates()); got != "c,a,b" {
+		t.Errorf("candidates = %s, want c first", got)
+	}
+}
+
+func TestPoolReturnsLastResponseWhenAllFail(t *testing.T) {
+	var calls []string
+	pool := newTestPool(t, hostResponses(map[string]int{"a": 500, "b": 500, "c": http.StatusServiceUnavailable}, nil, &calls))
+
+	resp, err := send(t, pool)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("RoundTrip = %v, %v, want the 503 of the last endpoint", resp, err)
+	}
+}
+
+func TestPoolThrottleIsNotAFailure(t *testing.T) {
+	var calls []string
+	header := http.Header{"Retry-After": []string{"30"}}
+	pool := newTestPool(t, hostResponses(map[string]int{"a": http.StatusTooManyRequests, "b": http.StatusOK, "c": http.StatusOK}, header, &calls))
+
+	resp, err := send(t, pool)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("RoundTrip = %v, %v, want 200 from b", resp, err)
+	}
+
+	a := pool.Status()[0]
+	if !a.Healthy || a.Score != 1 {
+		t.Errorf("throttled endpoint = %+v, want healthy with an untouched score", a)
+	}
+	if wait := time.Until(pool.endpoints[0].throttledUntil); wait < 29*time.Second || wait > 30*time.Second {
+		t.Errorf("throttled for %s, want the 30s of Retry-After", wait)
+	}
+	if got := hosts(pool.candidates()); got != "b,c,a" {
+		t.Errorf("candidates = %s, want the throttled endpoint last", got)
+	}
+}
+
+func TestPoolCandidatesRank(t *testing.T) {
+	pool := newTestPool(t, nil)
+	a, b, c := pool.endpoints[0], pool.endpoints[1], pool.endpoints[2]
+
+	// Down endpoints go after throttled ones, whatever their score
+	a.downUntil = time.Now().Add(time.Minute)
+	b.throttledUntil = time.Now().Add(time.Minute)
+	c.score = 0.5
+	if got := hosts(pool.candidates()); got != "c,b,a" {
+		t.Errorf("candidates = %s, want c,b,a", got)
+	}
+
+	// Among available endpoints the score wins, then the configured order
+	a.downUntil, b.throttledUntil = time.Time{}, time.Time{}
+	if got := hosts(pool.candidates()); got != "a,b,c" {
+		t.Errorf("candidates = %s, want a,b,c", got)
+	}
+}
+
+func TestPoolScoring(t *testing.T) {
+	pool := newTestPool(t, nil)
+	e := pool.endpoints[0]
+
+	pool.record(e, errors.New("boom"))
+	if e.score != scoreDecay || e.failures != 1 {
+		t.Fatalf("after a failure score = %v, failures = %d", e.score, e.failures)
+	}
+	if cooldown := time.Until(e.downUntil); cooldown <= 0 || cooldown > baseCooldown {
+		t.Errorf("cooldown = %s, want up to %s", cooldown, baseCooldown)
+	}
+
+	pool.record(e, errors.New("boom"))
+	if cooldown := time.Until(e.downUntil); cooldown <= baseCooldown || cooldown > 2*baseCooldown {
+		t.Errorf("second cooldown = %s, want doubled", cooldown)
+	}
+
+	want := e.score*scoreDecay + (1 - scoreDecay)
+	pool.record(e, nil)
+	if e.score != want || e.failures != 0 || !e.downUntil.IsZero() {
+		t.Errorf("after a success score = %v (want %v), failures = %d, downUntil = %v", e.score, want, e.failures, e.downUntil)
+	}
+
+	for range 10 {
+		pool.record(e, errors.New("boom"))
+	}
+	if cooldown := time.Until(e.downUntil); cooldown > maxCooldown {
+		t.Errorf("cooldown = %s, want at most %s", cooldown, maxCooldown)
+	}
+}
@@ -0,0 +1,81 @@
+package rpc_backend
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPStatusError is returned when the RPC server answers with a status that has no JSON-RPC body,
+// such as 429 from a rate limiting proxy
+type HTTPStatusError struct {
+	StatusCode int
+	Wait       time.Duration // Retry-After sent by the server, 0 if none
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("RPC server returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// HTTPStatus returns the response status code
+func (e *HTTPStatusError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// RetryAfter returns how long the server asked to wait before retrying
+func (e *HTTPStatusError) RetryAfter() time.Duration {
+	return e.Wait
+}
+
+// statusTransport turns rate limit and unavailable responses into HTTPStatusError,
+// so callers can classify them without parsing error messages
+type statusTransport struct {
+	base http.RoundTripper
+}
+
+func (t statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Wait: retryAfter(resp.Header.Get("Retry-After"))}
+}
+
+// withStatusErrors returns a copy of client whose transport reports rate limiting as HTTPStatusError
+func withStatusErrors(client *http.Client) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = statusTransport{base: base}
+	return &wrapped
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -0,0 +1,27 @@
+package rpc_backend
+
+import (
+	"context"
+	"fmt"
+)
+
+// getTransactionResult is the result of the getTransaction method
+type getTransactionResult struct {
+	Status string `json:"status"`
+	Ledger uint32 `json:"ledger"`
+}
+
+// LookupTransactionLedger asks the RPC server for the ledger that included a transaction.
+// Only transactions within the server's retention window can be found.
+func (c ClientConfig) LookupTransactionLedger(ctx context.Context, txHash string) (uint32, error) {
+	var result getTransactionResult
+	if err := c.call(ctx, "getTransaction", map[string]string{"hash": txHash}, &result); err != nil {
+		return 0, err
+	}
+
+	if result.Status == "NOT_FOUND" {
+		return 0, fmt.Errorf("transaction %s not found (it may be outside the RPC retention window)", txHash)
+	}
+
+	return result.Ledger, nil
+}
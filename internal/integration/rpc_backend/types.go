@@ -1,5 +1,7 @@
 package rpc_backend
 
+import "net/http"
+
 // BackendBuilder is a generic interface for building backend instances in a modular way
 type BackendBuilder[T any] interface {
 	Build() (*T, error)
@@ -15,6 +17,7 @@ type ClientTimeoutConfig struct {
 // ClientConfig contains the configuration for connecting to an RPC endpoint
 type ClientConfig struct {
 	Endpoint          string              // RPC server endpoint URL
+	HTTPClient        *http.Client        // Optional HTTP client (defaults to http.Client{})
 	BufferSize        int                 // Number of ledgers to buffer
 	NetworkPassphrase string              // Stellar network passphrase
 	TimeoutConfig     ClientTimeoutConfig // Timeout and retry configuration
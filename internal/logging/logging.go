@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Log formats accepted by Setup
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup installs a slog handler as the default logger. Output of the standard log
+// package goes through it too, with the caller as source if log.Lshortfile is set.
+func Setup(format string) error {
+	handler, err := newHandler(os.Stderr, format)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// newHandler builds the handler for format, adding the request ID of the context to each record
+func newHandler(w io.Writer, format string) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{AddSource: true}
+
+	switch format {
+	case FormatText, "":
+		return contextHandler{slog.NewTextHandler(w, opts)}, nil
+	case FormatJSON:
+		return contextHandler{slog.NewJSONHandler(w, opts)}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected %s or %s", format, FormatText, FormatJSON)
+	}
+}
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of ctx, empty when there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Printf logs like log.Printf, with the request ID of ctx attached
+func Printf(ctx context.Context, format string, args ...any) {
+	logger := slog.Default()
+	if !logger.Enabled(ctx, slog.LevelInfo) {
+		return
+	}
+
+	// Skip runtime.Callers and Printf so the source is the caller
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, fmt.Sprintf(format, args...), pcs[0])
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+// contextHandler adds the request ID found in the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,17 @@
+package metrics
+
+import "time"
+
+// startedAt marks when the process started; ledgers closed earlier are historical
+var startedAt = time.Now()
+
+// ObserveEventLatency records the close-to-consumer latency of an event.
+// Events from ledgers closed before the process started (backfills, catch-up,
+// re-decoded events) are skipped so they don't distort live freshness.
+func ObserveEventLatency(eventType string, ledgerCloseTime time.Time) {
+	if ledgerCloseTime.IsZero() || ledgerCloseTime.Before(startedAt) {
+		return
+	}
+
+	EventLatency.WithLabelValues(eventType).Observe(time.Since(ledgerCloseTime).Seconds())
+}
@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "indexer"
+
+// Registry holds every collector exposed by the indexer
+var Registry = prometheus.NewRegistry()
+
+var (
+	// LedgerIndexLatency measures the time between a ledger closing on the network and it being fully processed
+	LedgerIndexLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ledger_index_latency_seconds",
+		Help:      "Time between ledger close and the ledger being indexed",
+		Buckets:   []float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 120, 300, 600},
+	})
+
+	// LastIndexedLedger is the sequence of the most recent ledger processed
+	LastIndexedLedger = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_indexed_ledger",
+		Help:      "Sequence of the last ledger indexed",
+	})
+
+	// FreshnessSLOTarget exposes the configured freshness threshold so alert rules don't hardcode it
+	FreshnessSLOTarget = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "freshness_slo_target_seconds",
+		Help:      "Maximum close-to-indexed latency for a ledger to count as fresh",
+	})
+
+	// FreshnessSLOObjective exposes the configured fraction of ledgers that must be fresh
+	FreshnessSLOObjective = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "freshness_slo_objective_ratio",
+		Help:      "Fraction of ledgers that must be indexed within the freshness target",
+	})
+
+	// FreshnessSLOBurnRate is the error budget burn rate per lookback window (1 = burning exactly on budget)
+	FreshnessSLOBurnRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "freshness_slo_burn_rate",
+		Help:      "Freshness SLO error budget burn rate over the lookback window",
+	}, []string{"window"})
+
+	// EventLatency measures the time between a ledger closing and its events reaching consumers, per event type
+	EventLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "event_latency_seconds",
+		Help:      "Time between ledger close and the event being handed to consumers (webhooks)",
+		Buckets:   []float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 120, 300, 600},
+	}, []string{"event_type"})
+
+	// OutboundRequests counts outbound HTTP requests per destination host and egress decision
+	OutboundRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbound_requests_total",
+		Help:      "Outbound HTTP requests by destination host and result (allowed, blocked)",
+	}, []string{"host", "result"})
+
+	// WebhooksDropped counts webhook notifications dead-lettered without a delivery attempt
+	WebhooksDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhooks_dropped_total",
+		Help:      "Webhook notifications dead-lettered because the delivery queue was full",
+	}, []string{"event_type"})
+
+	// ProcessorDuration measures the time each processor spends per ledger and per transaction
+	ProcessorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "processor_duration_seconds",
+		Help:      "Time spent by a processor handling a ledger or a transaction",
+		Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+	}, []string{"processor", "stage"})
+
+	// ProcessorErrors counts errors returned by processors
+	ProcessorErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "processor_errors_total",
+		Help:      "Errors returned by a processor while handling a ledger or a transaction",
+	}, []string{"processor", "stage"})
+
+	// ProcessorTimeouts counts transactions a processor did not finish within the per-transaction timeout
+	ProcessorTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "processor_timeouts_total",
+		Help:      "Transactions abandoned because a processor exceeded the per-transaction timeout",
+	}, []string{"processor"})
+
+	// EventsEmitted counts events handed by processors to consumers
+	EventsEmitted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_emitted_total",
+		Help:      "Events emitted by a processor, per event type",
+	}, []string{"processor", "event_type"})
+
+	// EventsDropped counts events lost because the consumer buffer was full
+	EventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_dropped_total",
+		Help:      "Events dropped because the processor buffer was full",
+	}, []string{"processor"})
+
+	// FailedTransactions counts transactions a processor failed on, per processor
+	FailedTransactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_transactions_total",
+		Help:      "Transactions a processor failed to handle",
+	}, []string{"processor"})
+
+	// RPCEndpointRequests counts requests sent to each RPC endpoint of the failover pool
+	RPCEndpointRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_endpoint_requests_total",
+		Help:      "Requests sent to each RPC endpoint, by result",
+	}, []string{"endpoint", "result"})
+
+	// RPCEndpointScore is the health score of each RPC endpoint, 1 when every recent request succeeded
+	RPCEndpointScore = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rpc_endpoint_score",
+		Help:      "Health score of each RPC endpoint (0-1)",
+	}, []string{"endpoint"})
+
+	// RPCThrottled counts 429 responses received from each RPC endpoint
+	RPCThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_throttled_total",
+		Help:      "Rate limited (429) responses from each RPC endpoint",
+	}, []string{"endpoint"})
+
+	// RPCRateLimit is the current adaptive request rate towards each RPC endpoint, 0 when unlimited
+	RPCRateLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rpc_rate_limit",
+		Help:      "Current request rate limit towards each RPC endpoint (req/s, 0 = unlimited)",
+	}, []string{"endpoint"})
+
+	// LedgerRetries counts failed ledger attempts that were retried, per error class
+	LedgerRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ledger_retries_total",
+		Help:      "Failed ledger attempts that were retried",
+	}, []string{"class"})
+
+	// DecodeFailures counts events that could not be decoded and were quarantined
+	DecodeFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "decode_failures_total",
+		Help:      "Events quarantined because they could not be decoded",
+	}, []string{"processor"})
+
+	// DecodeFailuresResolved counts quarantined events successfully re-decoded
+	DecodeFailuresResolved = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "decode_failures_resolved_total",
+		Help:      "Quarantined events successfully re-decoded",
+	}, []string{"processor"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		LedgerIndexLatency,
+		LastIndexedLedger,
+		FreshnessSLOTarget,
+		FreshnessSLOObjective,
+		FreshnessSLOBurnRate,
+		EventLatency,
+		OutboundRequests,
+		WebhooksDropped,
+		ProcessorDuration,
+		ProcessorErrors,
+		ProcessorTimeouts,
+		EventsEmitted,
+		EventsDropped,
+		FailedTransactions,
+		LedgerRetries,
+		RPCEndpointRequests,
+		RPCEndpointScore,
+		RPCThrottled,
+		RPCRateLimit,
+		DecodeFailures,
+		DecodeFailuresResolved,
+	)
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Burn rate lookback windows, paired the usual way for fast and slow burn alerts
+var burnRateWindows = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+}
+
+// FreshnessSLOConfig defines the ingestion freshness objective
+type FreshnessSLOConfig struct {
+	Target    time.Duration // Maximum close-to-indexed latency for a ledger to be fresh
+	Objective float64       // Fraction of ledgers that must be fresh (e.g. 0.99)
+}
+
+// DefaultFreshnessSLO is 99% of ledgers indexed within 30 seconds of closing
+var DefaultFreshnessSLO = FreshnessSLOConfig{
+	Target:    30 * time.Second,
+	Objective: 0.99,
+}
+
+// freshnessSample is a single ledger observation
+type freshnessSample struct {
+	at    time.Time
+	fresh bool
+}
+
+// FreshnessTracker records per-ledger latency and keeps the burn rate gauges up to date
+type FreshnessTracker struct {
+	config  FreshnessSLOConfig
+	mu      sync.Mutex
+	samples []freshnessSample
+}
+
+// NewFreshnessTracker creates a tracker for the given SLO
+func NewFreshnessTracker(config FreshnessSLOConfig) *FreshnessTracker {
+	if config.Target <= 0 {
+		config.Target = DefaultFreshnessSLO.Target
+	}
+	if config.Objective <= 0 || config.Objective >= 1 {
+		config.Objective = DefaultFreshnessSLO.Objective
+	}
+
+	FreshnessSLOTarget.Set(config.Target.Seconds())
+	FreshnessSLOObjective.Set(config.Objective)
+
+	return &FreshnessTracker{config: config}
+}
+
+// ObserveLedger records that a ledger closed at closeTime has just been indexed
+func (t *FreshnessTracker) ObserveLedger(sequence uint32, closeTime time.Time) {
+	now := time.Now()
+	latency := now.Sub(closeTime)
+	if latency < 0 {
+		latency = 0
+	}
+
+	LedgerIndexLatency.Observe(latency.Seconds())
+	LastIndexedLedger.Set(float64(sequence))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.samples = append(t.samples, freshnessSample{at: now, fresh: latency <= t.config.Target})
+	t.prune(now)
+	t.updateBurnRates(now)
+}
+
+// prune drops samples older than the longest window
+func (t *FreshnessTracker) prune(now time.Time) {
+	oldest := now.Add(-burnRateWindows[len(burnRateWindows)-1].duration)
+
+	idx := 0
+	for idx < len(t.samples) && t.samples[idx].at.Before(oldest) {
+		idx++
+	}
+	t.samples = t.samples[idx:]
+}
+
+// updateBurnRates recomputes the burn rate of each window: the observed stale ratio divided by the allowed one
+func (t *FreshnessTracker) updateBurnRates(now time.Time) {
+	budget := 1 - t.config.Objective
+
+	for _, window := range burnRateWindows {
+		since := now.Add(-window.duration)
+
+		var total, stale int
+		for i := len(t.samples) - 1; i >= 0 && !t.samples[i].at.Before(since); i-- {
+			total++
+			if !t.samples[i].fresh {
+				stale++
+			}
+		}
+
+		burnRate := 0.0
+		if total > 0 {
+			burnRate = (float64(stale) / float64(total)) / budget
+		}
+		FreshnessSLOBurnRate.WithLabelValues(window.label).Set(burnRate)
+	}
+}
@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LoadSubscriptionsFile registers the subscriptions listed in a JSON file
+// (an array of {"url", "event_types", "secret"} objects). A missing file has no subscriptions.
+func LoadSubscriptionsFile(registry *Registry, path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading webhooks file: %w", err)
+	}
+
+	var subs []Subscription
+	if err := json.Unmarshal(data, &subs); err != nil {
+		return fmt.Errorf("invalid webhooks file %s: %w", path, err)
+	}
+
+	for _, sub := range subs {
+		if _, err := registry.Add(sub); err != nil {
+			return fmt.Errorf("invalid webhook in %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// saveSubscriptionsFile replaces the webhooks file with subs, IDs and secrets included.
+// The file is only readable by its owner since it holds the signing secrets.
+func saveSubscriptionsFile(path string, subs []Subscription) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(subs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,274 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"indexer/internal/metrics"
+)
+
+// Delivery headers sent with every webhook request
+const (
+	HeaderEvent     = "X-Indexer-Event"
+	HeaderDelivery  = "X-Indexer-Delivery"
+	HeaderSignature = "X-Indexer-Signature"
+)
+
+// DispatcherConfig holds the delivery settings
+type DispatcherConfig struct {
+	QueueSize   int           // Pending deliveries before new notifications are dead-lettered
+	Workers     int           // Concurrent deliveries
+	MaxAttempts int           // Attempts per delivery before dead-lettering
+	RetryWait   time.Duration // Initial wait between attempts, doubled after each failure
+	Timeout     time.Duration // HTTP timeout per attempt
+	Client      *http.Client  // Optional HTTP client (defaults to one with Timeout)
+}
+
+// delivery is a notification bound to one subscription
+type delivery struct {
+	notification Notification
+	subscription Subscription
+}
+
+// Dead letter errors of notifications that were never attempted
+var (
+	errQueueFull = errors.New("delivery queue full")
+	errShutdown  = errors.New("shutdown before delivery")
+)
+
+// Dispatcher delivers notifications to matching webhook subscriptions with retries and signing
+type Dispatcher struct {
+	config      DispatcherConfig
+	registry    *Registry
+	deadLetters DeadLetterStore
+	client      *http.Client
+	queue       chan delivery
+	pending     atomic.Int64 // Deliveries queued or being attempted, counted before they enter the queue
+
+	// Lifecycle control
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the registry's subscriptions
+func NewDispatcher(config DispatcherConfig, registry *Registry, deadLetters DeadLetterStore) *Dispatcher {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.RetryWait <= 0 {
+		config.RetryWait = time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Dispatcher{
+		config:      config,
+		registry:    registry,
+		deadLetters: deadLetters,
+		client:      client,
+		queue:       make(chan delivery, config.QueueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start launches the delivery workers
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+}
+
+// Publish queues a notification for every subscription matching eventType. It never blocks:
+// when the queue is full the notification is dead-lettered so it can be replayed.
+func (d *Dispatcher) Publish(eventType string, data any) {
+	notification := Notification{
+		ID:        newID(),
+		EventType: eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+
+	for _, sub := range d.registry.matching(eventType) {
+		d.pending.Add(1)
+		select {
+		case d.queue <- delivery{notification: notification, subscription: sub}:
+		default:
+			d.pending.Add(-1)
+			log.Printf("⚠️  Webhook queue full, dead-lettering %s notification for %s", eventType, sub.URL)
+			metrics.WebhooksDropped.WithLabelValues(eventType).Inc()
+			d.deadLetter(delivery{notification: notification, subscription: sub}, 0, errQueueFull)
+		}
+	}
+}
+
+// Stop cancels pending retries and waits for the workers to exit
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Drain waits until every queued notification has been delivered or ctx expires, then stops.
+// Notifications still queued are dead-lettered so they can be replayed.
+func (d *Dispatcher) Drain(ctx context.Context) {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+wait:
+	for d.pending.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			break wait
+		}
+	}
+
+	d.Stop()
+
+	for {
+		select {
+		case item := <-d.queue:
+			d.deadLetter(item, 0, errShutdown)
+			d.pending.Add(-1)
+		default:
+			return
+		}
+	}
+}
+
+// worker delivers queued notifications until the dispatcher is stopped
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case item := <-d.queue:
+			// Both cases can be ready after Stop, don't start a delivery that is already cancelled
+			if d.ctx.Err() != nil {
+				d.deadLetter(item, 0, errShutdown)
+			} else {
+				d.deliver(item)
+			}
+			d.pending.Add(-1)
+		}
+	}
+}
+
+// deliver sends a notification with exponential backoff, dead-lettering it after the last attempt
+func (d *Dispatcher) deliver(item delivery) {
+	body, err := json.Marshal(item.notification)
+	if err != nil {
+		log.Printf("❌ Error encoding webhook notification: %v", err)
+		return
+	}
+
+	wait := d.config.RetryWait
+
+	for attempt := 1; ; attempt++ {
+		err := d.send(item, body)
+		if err == nil {
+			return
+		}
+
+		log.Printf("⚠️  Webhook delivery to %s failed (attempt %d/%d): %v",
+			item.subscription.URL, attempt, d.config.MaxAttempts, err)
+
+		if attempt == d.config.MaxAttempts {
+			d.deadLetter(item, attempt, err)
+			return
+		}
+
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-d.ctx.Done():
+			d.deadLetter(item, attempt, fmt.Errorf("shutdown before delivery: %w", err))
+			return
+		}
+	}
+}
+
+// send performs a single delivery attempt
+func (d *Dispatcher) send(item delivery, body []byte) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, item.subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, item.notification.EventType)
+	req.Header.Set(HeaderDelivery, item.notification.ID)
+	if item.subscription.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(item.subscription.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// deadLetter persists a delivery that exhausted its attempts
+func (d *Dispatcher) deadLetter(item delivery, attempts int, deliveryErr error) {
+	if d.deadLetters == nil {
+		return
+	}
+
+	deadLetter := DeadLetter{
+		Notification:   item.notification,
+		SubscriptionID: item.subscription.ID,
+		URL:            item.subscription.URL,
+		Attempts:       attempts,
+		Error:          deliveryErr.Error(),
+		FailedAt:       time.Now().UTC(),
+	}
+
+	if err := d.deadLetters.SaveDeadLetter(context.Background(), deadLetter); err != nil {
+		log.Printf("❌ Error saving webhook dead letter: %v", err)
+	}
+}
+
+// Sign returns the signature header value for body: "sha256=" + hex(HMAC-SHA256(secret, body))
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,246 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"indexer/internal/egress"
+	"indexer/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// memoryDeadLetters collects dead letters in memory
+type memoryDeadLetters struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+func (m *memoryDeadLetters) SaveDeadLetter(ctx context.Context, deadLetter DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.letters = append(m.letters, deadLetter)
+	return nil
+}
+
+func (m *memoryDeadLetters) all() []DeadLetter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]DeadLetter(nil), m.letters...)
+}
+
+// testRegistry accepts the loopback addresses of httptest servers
+func testRegistry(t *testing.T, subs ...Subscription) *Registry {
+	t.Helper()
+
+	registry := NewRegistry(egress.NewPolicy([]string{"127.0.0.1"}))
+	for _, sub := range subs {
+		if _, err := registry.Add(sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return registry
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 test vector
+	got := Sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	want := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+
+	if got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	requests := make(chan *http.Request, 2)
+	bodies := make(chan []byte, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	registry := testRegistry(t,
+		Subscription{URL: server.URL + "/transfers", EventTypes: []string{"transfer"}, Secret: "s3cr3t"},
+		Subscription{URL: server.URL + "/deployments", EventTypes: []string{"deployment"}},
+	)
+	dispatcher := NewDispatcher(DispatcherConfig{}, registry, nil)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	dispatcher.Publish("transfer", map[string]string{"amount": "10"})
+
+	var r *http.Request
+	select {
+	case r = <-requests:
+	case <-time.After(time.Second):
+		t.Fatal("no delivery")
+	}
+	body := <-bodies
+
+	if r.URL.Path != "/transfers" {
+		t.Errorf("delivered to %s, want /transfers", r.URL.Path)
+	}
+	if got := r.Header.Get(HeaderEvent); got != "transfer" {
+		t.Errorf("%s = %q, want transfer", HeaderEvent, got)
+	}
+	if got, want := r.Header.Get(HeaderSignature), Sign("s3cr3t", body); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+
+	var notification Notification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		t.Fatal(err)
+	}
+	if notification.EventType != "transfer" || notification.ID != r.Header.Get(HeaderDelivery) {
+		t.Errorf("notification = %+v, delivery header %q", notification, r.Header.Get(HeaderDelivery))
+	}
+
+	select {
+	case r := <-requests:
+		t.Errorf("unexpected delivery to %s", r.URL.Path)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int32 // Attempts answered with 500 before succeeding
+		attempts    int32
+		deadLetters int
+	}{
+		{"succeeds after a retry", 1, 2, 0},
+		{"dead-lettered after the last attempt", 10, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+
+			deadLetters := &memoryDeadLetters{}
+			dispatcher := NewDispatcher(DispatcherConfig{MaxAttempts: 3, RetryWait: time.Millisecond},
+				testRegistry(t, Subscription{URL: server.URL}), deadLetters)
+			dispatcher.Start()
+
+			dispatcher.Publish("transfer", nil)
+			dispatcher.Drain(context.Background())
+
+			if got := calls.Load(); got != tt.attempts {
+				t.Errorf("attempts = %d, want %d", got, tt.attempts)
+			}
+
+			letters := deadLetters.all()
+			if len(letters) != tt.deadLetters {
+				t.Fatalf("dead letters = %d, want %d", len(letters), tt.deadLetters)
+			}
+			if tt.deadLetters > 0 && (letters[0].Attempts != 3 || letters[0].URL != server.URL) {
+				t.Errorf("dead letter = %+v", letters[0])
+			}
+		})
+	}
+}
+
+func TestPublishFullQueue(t *testing.T) {
+	registry := testRegistry(t,
+		Subscription{URL: "http://127.0.0.1/first"},
+		Subscription{URL: "http://127.0.0.1/second"},
+	)
+	deadLetters := &memoryDeadLetters{}
+
+	// Not started, so the single queue slot stays taken
+	dispatcher := NewDispatcher(DispatcherConfig{QueueSize: 1}, registry, deadLetters)
+	dropped := testutil.ToFloat64(metrics.WebhooksDropped.WithLabelValues("full_queue_test"))
+
+	dispatcher.Publish("full_queue_test", nil)
+
+	letters := deadLetters.all()
+	if len(letters) != 1 || letters[0].Attempts != 0 || letters[0].Error != errQueueFull.Error() {
+		t.Fatalf("dead letters = %+v, want one queue full entry", letters)
+	}
+	if len(dispatcher.queue) != 1 {
+		t.Errorf("queued = %d, want 1", len(dispatcher.queue))
+	}
+	if got := testutil.ToFloat64(metrics.WebhooksDropped.WithLabelValues("full_queue_test")); got != dropped+1 {
+		t.Errorf("%s = %v, want %v", "indexer_webhooks_dropped_total", got, dropped+1)
+	}
+}
+
+func TestDrainWaitsForDeliveries(t *testing.T) {
+	var delivered atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		delivered.Add(1)
+	}))
+	defer server.Close()
+
+	deadLetters := &memoryDeadLetters{}
+	dispatcher := NewDispatcher(DispatcherConfig{Workers: 2}, testRegistry(t, Subscription{URL: server.URL}), deadLetters)
+	dispatcher.Start()
+
+	for range 5 {
+		dispatcher.Publish("transfer", nil)
+	}
+	dispatcher.Drain(context.Background())
+
+	if got := delivered.Load(); got != 5 {
+		t.Errorf("delivered = %d before Drain returned, want 5", got)
+	}
+	if letters := deadLetters.all(); len(letters) != 0 {
+		t.Errorf("dead letters = %+v, want none", letters)
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	deadLetters := &memoryDeadLetters{}
+	dispatcher := NewDispatcher(DispatcherConfig{Workers: 1}, testRegistry(t, Subscription{URL: server.URL}), deadLetters)
+	dispatcher.Start()
+
+	// The first notification blocks the only worker, the second stays queued
+	dispatcher.Publish("transfer", nil)
+	dispatcher.Publish("transfer", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	dispatcher.Drain(ctx)
+
+	// Both are kept for replay: the in-flight one after its attempt, the queued one without any
+	letters := deadLetters.all()
+	if len(letters) != 2 {
+		t.Fatalf("dead letters = %+v, want 2", letters)
+	}
+	attempts := map[int]bool{letters[0].Attempts: true, letters[1].Attempts: true}
+	if !attempts[0] || !attempts[1] {
+		t.Errorf("dead letter attempts = %d and %d, want 0 and 1", letters[0].Attempts, letters[1].Attempts)
+	}
+	if got := dispatcher.pending.Load(); got != 0 {
+		t.Errorf("pending = %d after Drain, want 0", got)
+	}
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"indexer/internal/egress"
+)
+
+// Registry holds the webhook subscriptions in memory, optionally mirrored to a file
+type Registry struct {
+	mu            sync.RWMutex
+	subscriptions map[string]Subscription
+	egress        *egress.Policy
+	file          string // Rewritten after every change (empty = memory only)
+}
+
+// NewRegistry creates an empty subscription registry that only accepts URLs allowed by the egress policy.
+// Without an allowlist, URLs on loopback, private and link-local networks are rejected.
+func NewRegistry(egressPolicy *egress.Policy) *Registry {
+	return &Registry{
+		subscriptions: make(map[string]Subscription),
+		egress:        egressPolicy,
+	}
+}
+
+// Persist writes the subscriptions to path after every Add or Remove, so the ones
+// registered through the API survive a restart. Load the file before calling it.
+func (r *Registry) Persist(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.file = path
+}
+
+// Add validates and registers a subscription, assigning its ID
+func (r *Registry) Add(sub Subscription) (Subscription, error) {
+	parsed, err := url.Parse(sub.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Subscription{}, fmt.Errorf("invalid webhook URL %q", sub.URL)
+	}
+
+	if err := r.egress.CheckHost(parsed.Hostname()); err != nil {
+		return Subscription{}, err
+	}
+	if !r.egress.Enabled() {
+		if err := egress.CheckPublicHost(context.Background(), parsed.Hostname()); err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	if sub.ID == "" {
+		sub.ID = newID()
+	}
+	if sub.CreatedAt.IsZero() {
+		sub.CreatedAt = time.Now().UTC()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, replaced := r.subscriptions[sub.ID]
+	r.subscriptions[sub.ID] = sub
+
+	if err := r.save(); err != nil {
+		if replaced {
+			r.subscriptions[sub.ID] = previous
+		} else {
+			delete(r.subscriptions, sub.ID)
+		}
+		return Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+// Remove deletes a subscription, reporting whether it existed
+func (r *Registry) Remove(id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub, ok := r.subscriptions[id]
+	if !ok {
+		return false, nil
+	}
+	delete(r.subscriptions, id)
+
+	if err := r.save(); err != nil {
+		r.subscriptions[id] = sub
+		return true, err
+	}
+
+	return true, nil
+}
+
+// List returns every registered subscription, oldest first
+func (r *Registry) List() []Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.list()
+}
+
+// list returns the subscriptions ordered by creation, the caller holds the lock
+func (r *Registry) list() []Subscription {
+	subs := make([]Subscription, 0, len(r.subscriptions))
+	for _, sub := range r.subscriptions {
+		subs = append(subs, sub)
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		if !subs[i].CreatedAt.Equal(subs[j].CreatedAt) {
+			return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+		}
+		return subs[i].ID < subs[j].ID
+	})
+
+	return subs
+}
+
+// save writes the subscriptions to the persisted file, the caller holds the write lock
+func (r *Registry) save() error {
+	if r.file == "" {
+		return nil
+	}
+
+	if err := saveSubscriptionsFile(r.file, r.list()); err != nil {
+		return fmt.Errorf("error saving webhook subscriptions: %w", err)
+	}
+	return nil
+}
+
+// matching returns the subscriptions interested in the given event type
+func (r *Registry) matching(eventType string) []Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var subs []Subscription
+	for _, sub := range r.subscriptions {
+		if sub.Matches(eventType) {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+}
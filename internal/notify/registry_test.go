@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"indexer/internal/egress"
+)
+
+func TestRegistryAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		url       string
+		wantErr   error // nil with valid = false means any error
+		valid     bool
+	}{
+		{"public address", nil, "https://203.0.113.10/hook", nil, true},
+		{"not http", nil, "ftp://203.0.113.10/hook", nil, false},
+		{"no host", nil, "https:///hook", nil, false},
+		{"loopback", nil, "http://127.0.0.1:8080/hook", egress.ErrPrivateAddress, false},
+		{"localhost", nil, "http://localhost:8080/hook", egress.ErrPrivateAddress, false},
+		{"ipv6 loopback", nil, "http://[::1]/hook", egress.ErrPrivateAddress, false},
+		{"private network", nil, "http://10.1.2.3/hook", egress.ErrPrivateAddress, false},
+		{"cloud metadata", nil, "http://169.254.169.254/latest/meta-data", egress.ErrPrivateAddress, false},
+		{"allowlisted local host", []string{"localhost"}, "http://localhost:9000/hook", nil, true},
+		{"outside the allowlist", []string{"hooks.example.com"}, "https://203.0.113.10/hook", egress.ErrHostNotAllowed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(egress.NewPolicy(tt.allowlist))
+			sub, err := registry.Add(Subscription{URL: tt.url})
+
+			if tt.valid {
+				if err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+				if sub.ID == "" || sub.CreatedAt.IsZero() {
+					t.Errorf("Add() = %+v, want an ID and creation time", sub)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("Add() succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(registry.List()) != 0 {
+				t.Error("rejected subscription was registered")
+			}
+		})
+	}
+}
+
+func TestRegistryPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks", "subscriptions.json")
+
+	registry := NewRegistry(nil)
+	if err := LoadSubscriptionsFile(registry, path); err != nil {
+		t.Fatalf("loading a missing file: %v", err)
+	}
+	registry.Persist(path)
+
+	kept, err := registry.Add(Subscription{URL: "https://203.0.113.10/kept", EventTypes: []string{"transfer"}, Secret: "s3cr3t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := registry.Add(Subscription{URL: "https://203.0.113.10/removed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, err := registry.Remove(removed.ID); !found || err != nil {
+		t.Fatalf("Remove() = %v, %v", found, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file mode = %v, want 0600 since it holds secrets", perm)
+	}
+
+	// A restart sees the same subscriptions, secret and ID included
+	restarted := NewRegistry(nil)
+	if err := LoadSubscriptionsFile(restarted, path); err != nil {
+		t.Fatal(err)
+	}
+	subs := restarted.List()
+	if len(subs) != 1 || subs[0].ID != kept.ID || subs[0].Secret != "s3cr3t" || !subs[0].CreatedAt.Equal(kept.CreatedAt) {
+		t.Errorf("reloaded subscriptions = %+v, want only %+v", subs, kept)
+	}
+}
+
+func TestRegistryPersistFailure(t *testing.T) {
+	// The parent "directory" is a file, so every save fails
+	parent := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(parent, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry(nil)
+	sub, err := registry.Add(Subscription{URL: "https://203.0.113.10/hook"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry.Persist(filepath.Join(parent, "webhooks.json"))
+
+	if _, err := registry.Add(Subscription{URL: "https://203.0.113.10/other"}); err == nil {
+		t.Error("Add() succeeded although the file can't be written")
+	}
+	if found, err := registry.Remove(sub.ID); !found || err == nil {
+		t.Errorf("Remove() = %v, %v, want found with an error", found, err)
+	}
+
+	// Failed changes are rolled back so memory matches the file
+	if subs := registry.List(); len(subs) != 1 || subs[0].ID != sub.ID {
+		t.Errorf("subscriptions = %+v, want only %s", subs, sub.ID)
+	}
+}
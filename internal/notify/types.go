@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"time"
+)
+
+// Subscription is a webhook endpoint registered for a set of event types
+type Subscription struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`      // Empty means every event type
+	Secret     string    `json:"secret,omitempty"` // Used to sign deliveries, never returned by the API
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Matches reports whether the subscription wants events of the given type
+func (s Subscription) Matches(eventType string) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+// Notification is the payload delivered to webhook endpoints
+type Notification struct {
+	ID        string    `json:"id"`
+	EventType string    `json:"event_type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// DeadLetter records a notification that could not be delivered after all retries
+type DeadLetter struct {
+	Notification   Notification `json:"notification"`
+	SubscriptionID string       `json:"subscription_id"`
+	URL            string       `json:"url"`
+	Attempts       int          `json:"attempts"`
+	Error          string       `json:"error"`
+	FailedAt       time.Time    `json:"failed_at"`
+}
+
+// DeadLetterStore persists undeliverable notifications for later inspection or replay
+type DeadLetterStore interface {
+	SaveDeadLetter(ctx context.Context, deadLetter DeadLetter) error
+}
+
+// newID returns a random identifier for subscriptions and notifications
+func newID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
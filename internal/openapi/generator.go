@@ -0,0 +1,272 @@
+// Package openapi generates an OpenAPI 3 document from Go API models.
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// pathParam matches the {name} wildcards of a route path
+var pathParam = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// Operation documents one route of the API
+type Operation struct {
+	Method   string
+	Path     string // As registered in the mux, e.g. "/webhooks/{id}"
+	Summary  string
+	Tag      string
+	Query    []Param
+	Request  any // Body model, nil if none
+	Response any // Success body model, nil for an empty response
+	Status   int // Success status (default 200)
+	Public   bool
+}
+
+// Param is a query string parameter
+type Param struct {
+	Name        string
+	Type        string // JSON schema type: string, boolean, integer...
+	Description string
+}
+
+// Info describes the API as a whole
+type Info struct {
+	Title         string
+	Version       string
+	Description   string
+	APIKeyHeader  string // Header carrying the API key, empty if the API is open
+	ErrorResponse any    // Body of every error response
+}
+
+// Generator builds the document, collecting every struct type as a shared schema
+type Generator struct {
+	info       Info
+	operations []Operation
+	schemas    map[string]any
+}
+
+// NewGenerator creates a generator for an API
+func NewGenerator(info Info) *Generator {
+	return &Generator{info: info, schemas: make(map[string]any)}
+}
+
+// Add registers operations in the document
+func (g *Generator) Add(operations ...Operation) {
+	g.operations = append(g.operations, operations...)
+}
+
+// Document returns the OpenAPI document, ready to be encoded as JSON
+func (g *Generator) Document() map[string]any {
+	paths := make(map[string]map[string]any)
+
+	var errorSchema map[string]any
+	if g.info.ErrorResponse != nil {
+		errorSchema = g.schema(reflect.TypeOf(g.info.ErrorResponse))
+	}
+
+	for _, op := range g.operations {
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]any)
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = g.operation(op, errorSchema)
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       g.info.Title,
+			"version":     g.info.Version,
+			"description": g.info.Description,
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": g.schemas},
+	}
+
+	if g.info.APIKeyHeader != "" {
+		doc["components"].(map[string]any)["securitySchemes"] = map[string]any{
+			"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": g.info.APIKeyHeader},
+		}
+		doc["security"] = []any{map[string]any{"apiKey": []string{}}}
+	}
+
+	return doc
+}
+
+// Write encodes the document as indented JSON
+func (g *Generator) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g.Document())
+}
+
+// operation renders one route
+func (g *Generator) operation(op Operation, errorSchema map[string]any) map[string]any {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	success := map[string]any{"description": http.StatusText(status)}
+	if op.Response != nil {
+		success["content"] = jsonContent(g.schema(reflect.TypeOf(op.Response)))
+	}
+	responses := map[string]any{strconv.Itoa(status): success}
+	if errorSchema != nil {
+		responses["default"] = map[string]any{"description": "Error", "content": jsonContent(errorSchema)}
+	}
+
+	var params []any
+	for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		params = append(params, map[string]any{
+			"name": match[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+		})
+	}
+	for _, param := range op.Query {
+		params = append(params, map[string]any{
+			"name": param.Name, "in": "query", "description": param.Description, "schema": map[string]any{"type": param.Type},
+		})
+	}
+
+	rendered := map[string]any{
+		"summary":   op.Summary,
+		"responses": responses,
+	}
+	if op.Tag != "" {
+		rendered["tags"] = []string{op.Tag}
+	}
+	if len(params) > 0 {
+		rendered["parameters"] = params
+	}
+	if op.Request != nil {
+		rendered["requestBody"] = map[string]any{"required": true, "content": jsonContent(g.schema(reflect.TypeOf(op.Request)))}
+	}
+	if op.Public {
+		rendered["security"] = []any{}
+	}
+
+	return rendered
+}
+
+// schema maps a Go type to a JSON schema, registering structs under components/schemas
+func (g *Generator) schema(t reflect.Type) map[string]any {
+	t = indirect(t)
+
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+// structRef registers a struct schema once and returns a reference to it
+func (g *Generator) structRef(t reflect.Type) map[string]any {
+	ref := map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	if _, ok := g.schemas[t.Name()]; ok {
+		return ref
+	}
+
+	// Reserve the name first so self-referencing types terminate
+	g.schemas[t.Name()] = nil
+
+	properties := make(map[string]any)
+	var required []string
+	for _, field := range jsonFields(t) {
+		properties[field.name] = g.schema(field.Type)
+		if !field.optional {
+			required = append(required, field.name)
+		}
+	}
+
+	object := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	g.schemas[t.Name()] = object
+
+	return ref
+}
+
+// jsonContent wraps a schema as an application/json body
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// jsonField is a struct field as it appears in the JSON encoding
+type jsonField struct {
+	reflect.StructField
+	name     string
+	optional bool
+}
+
+// jsonFields returns the JSON-visible fields of t, flattening embedded structs like encoding/json
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(indirect(field.Type))...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{
+			StructField: field,
+			name:        name,
+			optional:    strings.Contains(opts, "omitempty") || field.Type.Kind() == reflect.Pointer,
+		})
+	}
+
+	return fields
+}
+
+// indirect unwraps pointer types
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,169 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Coordinator runs the chunks of a backfill job on a pool of workers, persisting each status change
+type Coordinator struct {
+	store   JobStore
+	runner  RangeRunner
+	workers int
+
+	mu  sync.RWMutex
+	job Job
+}
+
+// NewCoordinator loads the job for [start, end] from the store, or creates it if it doesn't exist yet
+func NewCoordinator(ctx context.Context, store JobStore, runner RangeRunner, start, end, chunkSize uint32, workers int) (*Coordinator, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	job, err := store.LoadJob(ctx, JobID(start, end))
+	if err != nil {
+		return nil, fmt.Errorf("error loading backfill job: %w", err)
+	}
+
+	if job == nil {
+		newJob := NewJob(start, end, chunkSize)
+		job = &newJob
+	} else {
+		log.Printf("♻️  Resuming backfill %s (%d/%d chunks completed)", job.ID, job.CompletedChunks(), len(job.Chunks))
+	}
+
+	return &Coordinator{
+		store:   store,
+		runner:  runner,
+		workers: workers,
+		job:     *job,
+	}, nil
+}
+
+// Run processes every chunk that isn't completed yet and blocks until all workers finish
+func (c *Coordinator) Run(ctx context.Context) error {
+	// Chunks left running by a crash are retried from their start
+	for i := range c.job.Chunks {
+		if c.job.Chunks[i].Status == ChunkRunning {
+			c.setChunkStatus(ctx, i, ChunkPending, nil)
+		}
+	}
+
+	if err := c.persist(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("🚀 Running backfill %s with %d workers (%d chunks)", c.job.ID, c.workers, len(c.job.Chunks))
+
+	pending := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < c.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range pending {
+				c.runChunk(ctx, idx)
+			}
+		}()
+	}
+
+	// Feed chunks to workers in ledger order
+feed:
+	for i, chunk := range c.Snapshot().Chunks {
+		if chunk.Status == ChunkCompleted {
+			continue
+		}
+		select {
+		case pending <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(pending)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	job := c.Snapshot()
+	if !job.Done() {
+		return fmt.Errorf("backfill %s finished with %d/%d chunks completed", job.ID, job.CompletedChunks(), len(job.Chunks))
+	}
+
+	log.Printf("✅ Backfill %s completed", job.ID)
+
+	return nil
+}
+
+// runChunk processes a single chunk and records the outcome
+func (c *Coordinator) runChunk(ctx context.Context, idx int) {
+	chunk := c.Snapshot().Chunks[idx]
+
+	c.setChunkStatus(ctx, idx, ChunkRunning, nil)
+
+	err := c.runner(ctx, chunk.Start, chunk.End)
+	if err != nil {
+		// Leave interrupted chunks as running so they are retried on resume
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("❌ Backfill chunk %d-%d failed: %v", chunk.Start, chunk.End, err)
+		c.setChunkStatus(ctx, idx, ChunkFailed, err)
+		return
+	}
+
+	c.setChunkStatus(ctx, idx, ChunkCompleted, nil)
+
+	job := c.Snapshot()
+	log.Printf("📦 Backfill chunk %d-%d completed (%.1f%%)", chunk.Start, chunk.End, job.PercentComplete())
+}
+
+// setChunkStatus updates a chunk and persists the job
+func (c *Coordinator) setChunkStatus(ctx context.Context, idx int, status ChunkStatus, chunkErr error) {
+	c.mu.Lock()
+	c.job.Chunks[idx].Status = status
+	c.job.Chunks[idx].Error = ""
+	if chunkErr != nil {
+		c.job.Chunks[idx].Error = chunkErr.Error()
+	}
+	c.job.Chunks[idx].UpdatedAt = time.Now().UTC()
+	c.mu.Unlock()
+
+	// Status is persisted even when ctx is cancelled so progress survives shutdown
+	if err := c.persist(context.WithoutCancel(ctx)); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+}
+
+// persist saves the current job state
+func (c *Coordinator) persist(ctx context.Context) error {
+	if err := c.store.SaveJob(ctx, c.Snapshot()); err != nil {
+		return fmt.Errorf("error saving backfill job: %w", err)
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the job state
+func (c *Coordinator) Snapshot() Job {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	job := c.job
+	job.Chunks = append([]Chunk(nil), c.job.Chunks...)
+	return job
+}
+
+// BackfillJob returns the job if id matches the one being coordinated
+func (c *Coordinator) BackfillJob(id string) (Job, bool) {
+	job := c.Snapshot()
+	if job.ID != id {
+		return Job{}, false
+	}
+	return job, true
+}
@@ -0,0 +1,172 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// memoryStore keeps jobs in memory
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{jobs: make(map[string]Job)}
+}
+
+func (s *memoryStore) SaveJob(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *memoryStore) LoadJob(ctx context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	job.Chunks = slices.Clone(job.Chunks)
+	return &job, nil
+}
+
+// recordingRunner records the start of every chunk it runs and fails the ones in fail
+type recordingRunner struct {
+	mu   sync.Mutex
+	ran  []uint32
+	fail map[uint32]bool
+}
+
+func (r *recordingRunner) run(ctx context.Context, start, end uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ran = append(r.ran, start)
+	if r.fail[start] {
+		return errors.New("rpc unavailable")
+	}
+	return nil
+}
+
+func (r *recordingRunner) starts() []uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	starts := slices.Clone(r.ran)
+	slices.Sort(starts)
+	return starts
+}
+
+func statuses(job Job) []ChunkStatus {
+	var result []ChunkStatus
+	for _, chunk := range job.Chunks {
+		result = append(result, chunk.Status)
+	}
+	return result
+}
+
+func TestCoordinatorResumesUnfinishedChunks(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	// A previous run crashed with one chunk done, one running and one failed
+	job := NewJob(100, 139, 10)
+	job.Chunks[0].Status = ChunkCompleted
+	job.Chunks[1].Status = ChunkRunning
+	job.Chunks[2].Status = ChunkFailed
+	job.Chunks[2].Error = "rpc unavailable"
+	if err := store.SaveJob(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &recordingRunner{}
+	coordinator, err := NewCoordinator(ctx, store, runner.run, 100, 139, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := coordinator.Run(ctx); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+
+	if got, want := runner.starts(), []uint32{110, 120, 130}; !slices.Equal(got, want) {
+		t.Errorf("ran chunks %v, want %v", got, want)
+	}
+
+	saved, _ := store.LoadJob(ctx, job.ID)
+	if !saved.Done() || saved.Chunks[2].Error != "" {
+		t.Errorf("saved job = %v, want every chunk completed without error", statuses(*saved))
+	}
+}
+
+func TestCoordinatorRecordsFailedChunks(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+
+	runner := &recordingRunner{fail: map[uint32]bool{10: true}}
+	coordinator, err := NewCoordinator(ctx, store, runner.run, 0, 29, 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := coordinator.Run(ctx); err == nil {
+		t.Fatal("Run succeeded with a failed chunk")
+	}
+
+	saved, _ := store.LoadJob(ctx, JobID(0, 29))
+	if got, want := statuses(*saved), []ChunkStatus{ChunkCompleted, ChunkFailed, ChunkCompleted}; !slices.Equal(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+	if saved.Chunks[1].Error == "" {
+		t.Error("failed chunk has no error")
+	}
+
+	// Running the same backfill again only retries the failed chunk
+	retry := &recordingRunner{}
+	coordinator, err = NewCoordinator(ctx, store, retry.run, 0, 29, 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := coordinator.Run(ctx); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	if got := retry.starts(); !slices.Equal(got, []uint32{10}) {
+		t.Errorf("retried chunks %v, want [10]", got)
+	}
+}
+
+func TestCoordinatorLeavesInterruptedChunksRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := newMemoryStore()
+
+	runner := func(ctx context.Context, start, end uint32) error {
+		cancel()
+		return ctx.Err()
+	}
+	coordinator, err := NewCoordinator(ctx, store, runner, 0, 9, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := coordinator.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+
+	saved, _ := store.LoadJob(context.Background(), JobID(0, 9))
+	if saved.Chunks[0].Status != ChunkRunning {
+		t.Errorf("interrupted chunk is %s, want running so it is retried on resume", saved.Chunks[0].Status)
+	}
+}
+
+func TestNewJobChunks(t *testing.T) {
+	job := NewJob(5, 29, 10)
+
+	var ranges [][2]uint32
+	for _, chunk := range job.Chunks {
+		ranges = append(ranges, [2]uint32{chunk.Start, chunk.End})
+	}
+	if want := [][2]uint32{{5, 14}, {15, 24}, {25, 29}}; !slices.Equal(ranges, want) {
+		t.Errorf("chunks = %v, want %v", ranges, want)
+	}
+}
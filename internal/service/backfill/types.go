@@ -0,0 +1,110 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChunkStatus is the processing state of a backfill chunk
+type ChunkStatus string
+
+const (
+	ChunkPending   ChunkStatus = "pending"
+	ChunkRunning   ChunkStatus = "running"
+	ChunkCompleted ChunkStatus = "completed"
+	ChunkFailed    ChunkStatus = "failed"
+)
+
+// Chunk is a contiguous slice of the backfill range processed by a single worker
+type Chunk struct {
+	Start     uint32      `json:"start"`
+	End       uint32      `json:"end"`
+	Status    ChunkStatus `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Job describes a bounded backfill split into chunks
+type Job struct {
+	ID        string    `json:"id"`
+	Start     uint32    `json:"start"`
+	End       uint32    `json:"end"`
+	ChunkSize uint32    `json:"chunk_size"`
+	Chunks    []Chunk   `json:"chunks"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// JobStore persists backfill jobs so they can be resumed after a crash
+type JobStore interface {
+	SaveJob(ctx context.Context, job Job) error
+	LoadJob(ctx context.Context, id string) (*Job, error) // nil when the job doesn't exist
+}
+
+// RangeRunner processes a bounded ledger range, blocking until it completes or ctx is cancelled
+type RangeRunner func(ctx context.Context, start, end uint32) error
+
+// JobID returns the identifier of the backfill covering [start, end]
+func JobID(start, end uint32) string {
+	return fmt.Sprintf("%d_%d", start, end)
+}
+
+// NewJob splits [start, end] into chunks of at most chunkSize ledgers
+func NewJob(start, end, chunkSize uint32) Job {
+	if chunkSize == 0 {
+		chunkSize = end - start + 1
+	}
+
+	job := Job{
+		ID:        JobID(start, end),
+		Start:     start,
+		End:       end,
+		ChunkSize: chunkSize,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for chunkStart := uint64(start); chunkStart <= uint64(end); chunkStart += uint64(chunkSize) {
+		chunkEnd := min(chunkStart+uint64(chunkSize)-1, uint64(end))
+		job.Chunks = append(job.Chunks, Chunk{
+			Start:     uint32(chunkStart),
+			End:       uint32(chunkEnd),
+			Status:    ChunkPending,
+			UpdatedAt: job.CreatedAt,
+		})
+	}
+
+	return job
+}
+
+// CompletedChunks returns how many chunks have finished successfully
+func (j Job) CompletedChunks() int {
+	completed := 0
+	for _, chunk := range j.Chunks {
+		if chunk.Status == ChunkCompleted {
+			completed++
+		}
+	}
+	return completed
+}
+
+// PercentComplete returns the share of ledgers in completed chunks
+func (j Job) PercentComplete() float64 {
+	total := uint64(j.End) - uint64(j.Start) + 1
+	if total == 0 {
+		return 0
+	}
+
+	var done uint64
+	for _, chunk := range j.Chunks {
+		if chunk.Status == ChunkCompleted {
+			done += uint64(chunk.End) - uint64(chunk.Start) + 1
+		}
+	}
+
+	return float64(done) * 100 / float64(total)
+}
+
+// Done reports whether every chunk has completed
+func (j Job) Done() bool {
+	return j.CompletedChunks() == len(j.Chunks)
+}
@@ -0,0 +1,85 @@
+package datalake
+
+import (
+	"context"
+
+	"indexer/internal/integration/datalake_backend"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+)
+
+// LedgerBackend implements the ledger backend handler on top of a Galexie data lake
+type LedgerBackend struct {
+	ClientConfig datalake_backend.ClientConfig
+	backend      ledgerbackend.LedgerBackend
+	buildErr     error
+	isAvailable  bool
+}
+
+// Start initializes the ledger backend by building the datastore and buffered storage backend
+func (l *LedgerBackend) Start() error {
+
+	// Build the new backend instance
+	backendBuilder := datalake_backend.LedgerBuilder{
+		ClientConfig: l.ClientConfig,
+	}
+
+	backend, err := backendBuilder.Build()
+
+	if err != nil {
+		l.buildErr = err
+		l.isAvailable = false
+		return err
+	}
+
+	// Set the backend and mark it as available
+	l.backend = backend
+	l.isAvailable = true
+
+	return nil
+}
+
+// Close gracefully shuts down the ledger backend
+func (l *LedgerBackend) Close() error {
+	l.isAvailable = false
+	if l.backend != nil {
+		return l.backend.Close()
+	}
+	return nil
+}
+
+// IsAvailable returns whether the backend is ready for use
+func (l *LedgerBackend) IsAvailable() bool {
+	return l.isAvailable
+}
+
+// HandleBackend returns the underlying ledger backend instance
+func (l *LedgerBackend) HandleBackend() (ledgerbackend.LedgerBackend, error) {
+	return l.backend, l.buildErr
+}
+
+// PrepareRange configures the backend to read ledgers within the specified range
+func (l *LedgerBackend) PrepareRange(ctx context.Context, start, end *uint32) error {
+	var ledgerRange ledgerbackend.Range
+
+	if end == nil {
+		// Unbounded range, follows new files as Galexie exports them
+		ledgerRange = ledgerbackend.UnboundedRange(*start)
+	} else {
+		// Bounded range for a specific ledger range
+		ledgerRange = ledgerbackend.BoundedRange(*start, *end)
+	}
+
+	return l.backend.PrepareRange(ctx, ledgerRange)
+}
+
+// GetLatestLedgerSequence returns the most recent ledger sequence available in the buffer
+func (l *LedgerBackend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+
+	sequence, err := l.backend.GetLatestLedgerSequence(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return sequence, nil
+}
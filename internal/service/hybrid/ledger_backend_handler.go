@@ -0,0 +1,194 @@
+package hybrid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"indexer/internal/service/rpc"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerBackend reads historical ledgers from an archive backend (data lake) and hands off
+// to a live backend (RPC) once ingestion is within HandoffDistance ledgers of the network tip
+type LedgerBackend struct {
+	Archive         rpc.LedgerBackendHandlerService           // Source for deep history
+	Live            rpc.LedgerBackendHandlerService           // Source near the tip
+	HandoffDistance uint32                                    // Distance to the tip at which reads switch to Live
+	NetworkTip      func(ctx context.Context) (uint32, error) // Reads the network tip, e.g. from RPC getHealth
+	backend         *switchingBackend
+	isAvailable     bool
+}
+
+// Start initializes both underlying backends
+func (l *LedgerBackend) Start() error {
+	// Live can't report the tip before it is prepared, which only happens at handoff
+	if l.NetworkTip == nil {
+		return errors.New("hybrid backend needs a network tip source")
+	}
+
+	if err := l.Archive.Start(); err != nil {
+		return fmt.Errorf("error starting archive backend: %w", err)
+	}
+
+	if err := l.Live.Start(); err != nil {
+		l.Archive.Close()
+		return fmt.Errorf("error starting live backend: %w", err)
+	}
+
+	l.backend = &switchingBackend{
+		archive:         l.Archive,
+		live:            l.Live,
+		handoffDistance: l.HandoffDistance,
+		networkTip:      l.NetworkTip,
+	}
+	l.isAvailable = true
+
+	return nil
+}
+
+// Close gracefully shuts down both backends
+func (l *LedgerBackend) Close() error {
+	l.isAvailable = false
+	if l.backend != nil {
+		return l.backend.Close()
+	}
+	return nil
+}
+
+// IsAvailable returns whether the backend is ready for use
+func (l *LedgerBackend) IsAvailable() bool {
+	return l.isAvailable
+}
+
+// HandleBackend returns the ledger backend routing reads to the archive or the live source
+func (l *LedgerBackend) HandleBackend() (ledgerbackend.LedgerBackend, error) {
+	if l.backend == nil {
+		return nil, errors.New("hybrid backend not started")
+	}
+	return l.backend, nil
+}
+
+// PrepareRange prepares the range on the archive, the live backend is prepared at handoff
+func (l *LedgerBackend) PrepareRange(ctx context.Context, start, end *uint32) error {
+	var ledgerRange ledgerbackend.Range
+
+	if end == nil {
+		ledgerRange = ledgerbackend.UnboundedRange(*start)
+	} else {
+		ledgerRange = ledgerbackend.BoundedRange(*start, *end)
+	}
+
+	return l.backend.PrepareRange(ctx, ledgerRange)
+}
+
+// GetLatestLedgerSequence returns the network tip
+func (l *LedgerBackend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	return l.backend.GetLatestLedgerSequence(ctx)
+}
+
+// switchingBackend implements ledgerbackend.LedgerBackend on top of the two sources
+type switchingBackend struct {
+	archive         rpc.LedgerBackendHandlerService
+	live            rpc.LedgerBackendHandlerService
+	handoffDistance uint32
+	networkTip      func(ctx context.Context) (uint32, error)
+
+	ledgerRange ledgerbackend.Range
+	onLive      bool
+	knownTip    uint32
+}
+
+// GetLatestLedgerSequence returns the network tip
+func (b *switchingBackend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	return b.networkTip(ctx)
+}
+
+// GetLedger reads the ledger from the archive until it is close enough to the tip, then from the live backend
+func (b *switchingBackend) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	if !b.onLive {
+		if err := b.maybeHandoff(ctx, sequence); err != nil {
+			return xdr.LedgerCloseMeta{}, err
+		}
+	}
+
+	backend, err := b.current().HandleBackend()
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+
+	return backend.GetLedger(ctx, sequence)
+}
+
+// PrepareRange prepares the range on the archive backend
+func (b *switchingBackend) PrepareRange(ctx context.Context, ledgerRange ledgerbackend.Range) error {
+	b.ledgerRange = ledgerRange
+	b.onLive = false
+
+	backend, err := b.archive.HandleBackend()
+	if err != nil {
+		return err
+	}
+
+	return backend.PrepareRange(ctx, ledgerRange)
+}
+
+// IsPrepared reports whether the range is prepared on the backend currently serving reads
+func (b *switchingBackend) IsPrepared(ctx context.Context, ledgerRange ledgerbackend.Range) (bool, error) {
+	backend, err := b.current().HandleBackend()
+	if err != nil {
+		return false, err
+	}
+
+	return backend.IsPrepared(ctx, ledgerRange)
+}
+
+// Close shuts down both backends
+func (b *switchingBackend) Close() error {
+	return errors.Join(b.archive.Close(), b.live.Close())
+}
+
+// current returns the backend serving reads
+func (b *switchingBackend) current() rpc.LedgerBackendHandlerService {
+	if b.onLive {
+		return b.live
+	}
+	return b.archive
+}
+
+// maybeHandoff switches reads to the live backend once sequence is within the handoff distance of the tip.
+// The tip is only re-read when the cached value says the handoff could be due, since it only moves forward.
+func (b *switchingBackend) maybeHandoff(ctx context.Context, sequence uint32) error {
+	if sequence+b.handoffDistance < b.knownTip {
+		return nil
+	}
+
+	tip, err := b.networkTip(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting network tip: %w", err)
+	}
+	b.knownTip = tip
+
+	if sequence+b.handoffDistance < tip {
+		return nil
+	}
+
+	start := sequence
+	var end *uint32
+	if b.ledgerRange.Bounded() {
+		to := b.ledgerRange.To()
+		end = &to
+	}
+
+	if err := b.live.PrepareRange(ctx, &start, end); err != nil {
+		return fmt.Errorf("error preparing live backend at ledger %d: %w", sequence, err)
+	}
+
+	log.Printf("🔀 Switching from data lake to RPC at ledger %d (network tip %d)", sequence, tip)
+	b.onLive = true
+
+	return nil
+}
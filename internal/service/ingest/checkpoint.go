@@ -0,0 +1,91 @@
+package ingest
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+// checkpointer decides when progress is saved: every N ledgers or every T, whichever comes first
+type checkpointer struct {
+	everyLedgers uint32        // 0 = no ledger trigger
+	interval     time.Duration // 0 = no time trigger
+	lastSaved    uint32
+	lastSavedAt  time.Time
+}
+
+// due reports whether the ledger just processed should be saved
+func (c *checkpointer) due(sequence uint32, now time.Time) bool {
+	if c.lastSavedAt.IsZero() {
+		// Start counting from the first ledger processed
+		c.lastSaved = sequence - 1
+		c.lastSavedAt = now
+	}
+
+	if c.everyLedgers > 0 && sequence-c.lastSaved >= c.everyLedgers {
+		return true
+	}
+	return c.interval > 0 && now.Sub(c.lastSavedAt) >= c.interval
+}
+
+// saved records a successful save
+func (c *checkpointer) saved(sequence uint32, now time.Time) {
+	c.lastSaved = sequence
+	c.lastSavedAt = now
+}
+
+// reset starts counting again from the next ledger processed, after ingestion moved
+func (c *checkpointer) reset() {
+	c.lastSaved = 0
+	c.lastSavedAt = time.Time{}
+}
+
+// maybeCheckpoint saves the ledger just processed when the checkpoint policy says so
+func (s *OrchestratorService) maybeCheckpoint(sequence uint32) {
+	if s.checkpointMgr == nil {
+		return
+	}
+
+	now := time.Now()
+	if !s.checkpoints.due(sequence, now) {
+		return
+	}
+
+	if err := s.saveCheckpoint(s.ctx, sequence); err != nil {
+		log.Printf("⚠️  Error saving checkpoint at ledger %d: %v", sequence, err)
+		return
+	}
+	s.checkpoints.saved(sequence, now)
+}
+
+// saveCheckpoint saves sequence, along with its hash when it is the last ledger processed and the store keeps hashes
+func (s *OrchestratorService) saveCheckpoint(ctx context.Context, sequence uint32) error {
+	store, ok := s.checkpointMgr.(LedgerHashCheckpointStore)
+	if !ok || sequence != s.lastLedgerSeq || s.lastLedgerHash == (xdr.Hash{}) {
+		return s.checkpointMgr.Save(ctx, sequence)
+	}
+	return store.SaveWithHash(ctx, sequence, s.lastLedgerHash)
+}
+
+// restoreLastLedgerHash resumes the chain continuity check from the checkpointed ledger when ingestion
+// continues right after it, so a chain reset while the indexer was stopped fails the first ledger
+func (s *OrchestratorService) restoreLastLedgerHash(startLedger uint32) {
+	store, ok := s.checkpointMgr.(LedgerHashCheckpointStore)
+	if !ok {
+		return
+	}
+
+	sequence, hash, err := store.LoadHash(s.ctx)
+	if err != nil {
+		log.Printf("⚠️  Error loading checkpoint hash, chain continuity is checked from the first ledger: %v", err)
+		return
+	}
+	if sequence == 0 || sequence+1 != startLedger || hash == (xdr.Hash{}) {
+		return
+	}
+
+	s.lastLedgerSeq = sequence
+	s.lastLedgerHash = hash
+}
@@ -0,0 +1,74 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckpointerDueEveryLedgers(t *testing.T) {
+	c := checkpointer{everyLedgers: 3}
+	now := time.Now()
+
+	// Counting starts at the first ledger processed, not at ledger 0
+	if c.due(1000, now) || c.due(1001, now) {
+		t.Fatal("due before 3 ledgers were processed")
+	}
+	if !c.due(1002, now) {
+		t.Fatal("due(1002) = false, want true after 3 ledgers")
+	}
+
+	c.saved(1002, now)
+	if c.due(1003, now) || c.due(1004, now) || !c.due(1005, now) {
+		t.Error("not due again 3 ledgers after the save")
+	}
+}
+
+func TestCheckpointerDueInterval(t *testing.T) {
+	c := checkpointer{interval: time.Minute}
+	start := time.Now()
+
+	if c.due(1000, start) {
+		t.Fatal("due on the first ledger")
+	}
+	if c.due(5000, start.Add(59*time.Second)) {
+		t.Error("due before the interval without a ledger trigger")
+	}
+	if !c.due(5001, start.Add(time.Minute)) {
+		t.Error("not due once the interval elapsed")
+	}
+
+	c.saved(5001, start.Add(time.Minute))
+	if c.due(5002, start.Add(90*time.Second)) {
+		t.Error("due before the interval since the last save")
+	}
+}
+
+func TestCheckpointerWhicheverComesFirst(t *testing.T) {
+	c := checkpointer{everyLedgers: 100, interval: time.Minute}
+	start := time.Now()
+
+	c.due(1, start)
+	if !c.due(2, start.Add(time.Minute)) {
+		t.Error("interval did not trigger before the ledger count")
+	}
+	if !c.due(100, start) {
+		t.Error("ledger count did not trigger before the interval")
+	}
+}
+
+func TestCheckpointerReset(t *testing.T) {
+	c := checkpointer{everyLedgers: 10}
+	now := time.Now()
+
+	c.due(1000, now)
+	c.saved(1005, now)
+
+	// After a seek backwards the count restarts at the new position instead of underflowing
+	c.reset()
+	if c.due(200, now) {
+		t.Error("due on the first ledger after reset")
+	}
+	if !c.due(209, now) {
+		t.Error("not due 10 ledgers after reset")
+	}
+}
@@ -0,0 +1,162 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"indexer/internal/logging"
+
+	"github.com/stellar/go/xdr"
+)
+
+// ErrNotRunning is returned by control commands when the live ingestion loop is not running
+var ErrNotRunning = errors.New("live ingestion is not running")
+
+// ErrSeekAheadOfTip is returned when seeking past the ledger the network will close next
+var ErrSeekAheadOfTip = errors.New("ledger is ahead of the network tip")
+
+// ControlState is the state of the live ingestion loop after a control command
+type ControlState struct {
+	Paused     bool   `json:"paused"`
+	NextLedger uint32 `json:"next_ledger"`
+}
+
+// controlKind identifies a control command
+type controlKind int
+
+const (
+	controlPause controlKind = iota
+	controlResume
+	controlSeek
+)
+
+// controlCommand is sent to the ingestion loop, which answers on reply
+type controlCommand struct {
+	ctx    context.Context // Context of the caller, for log correlation
+	kind   controlKind
+	ledger uint32
+	reply  chan controlReply
+}
+
+type controlReply struct {
+	state ControlState
+	err   error
+}
+
+// Pause stops processing new ledgers until Resume is called
+func (s *OrchestratorService) Pause(ctx context.Context) (ControlState, error) {
+	return s.sendControl(ctx, controlCommand{kind: controlPause})
+}
+
+// Resume continues processing after Pause
+func (s *OrchestratorService) Resume(ctx context.Context) (ControlState, error) {
+	return s.sendControl(ctx, controlCommand{kind: controlResume})
+}
+
+// Seek moves ingestion to ledger, re-preparing the backend there and saving the new position as the checkpoint
+func (s *OrchestratorService) Seek(ctx context.Context, ledger uint32) (ControlState, error) {
+	if ledger == 0 {
+		return ControlState{}, fmt.Errorf("invalid ledger 0")
+	}
+	return s.sendControl(ctx, controlCommand{kind: controlSeek, ledger: ledger})
+}
+
+// sendControl hands a command to the ingestion loop and waits for the result
+func (s *OrchestratorService) sendControl(ctx context.Context, cmd controlCommand) (ControlState, error) {
+	cmd.ctx = ctx
+	cmd.reply = make(chan controlReply, 1)
+
+	select {
+	case s.control <- cmd:
+	case <-s.loopDone:
+		return ControlState{}, ErrNotRunning
+	case <-ctx.Done():
+		return ControlState{}, ctx.Err()
+	}
+
+	select {
+	case reply := <-cmd.reply:
+		return reply.state, reply.err
+	case <-ctx.Done():
+		return ControlState{}, ctx.Err()
+	}
+}
+
+// handleControl applies a command inside the ingestion loop and returns the ledger to process next
+func (s *OrchestratorService) handleControl(cmd controlCommand, paused *bool, currentLedger uint32) uint32 {
+	var err error
+
+	switch cmd.kind {
+	case controlPause:
+		if !*paused {
+			logging.Printf(cmd.ctx, "⏸️  Ingestion paused at ledger %d", currentLedger)
+		}
+		*paused = true
+		s.reportLiveIdle()
+	case controlResume:
+		if *paused {
+			logging.Printf(cmd.ctx, "▶️  Ingestion resumed at ledger %d", currentLedger)
+		}
+		*paused = false
+	case controlSeek:
+		s.stopPrefetch()
+		if err = s.seek(cmd.ledger, currentLedger); err == nil {
+			logging.Printf(cmd.ctx, "⏩ Ingestion moved from ledger %d to %d", currentLedger, cmd.ledger)
+			currentLedger = cmd.ledger
+		}
+	}
+
+	s.progress.setPaused(*paused)
+	cmd.reply <- controlReply{state: ControlState{Paused: *paused, NextLedger: currentLedger}, err: err}
+
+	return currentLedger
+}
+
+// seek restarts the backend at ledger and persists the new position. On failure the backend
+// is prepared again at currentLedger, so the loop keeps going where it was.
+func (s *OrchestratorService) seek(ledger, currentLedger uint32) error {
+	latest, err := s.latestNetworkLedger(s.ctx)
+	if err != nil {
+		return fmt.Errorf("error getting network tip: %w", err)
+	}
+	if ledger > latest+1 {
+		return fmt.Errorf("%w: ledger %d, tip %d", ErrSeekAheadOfTip, ledger, latest)
+	}
+
+	if err := s.restartBackend(ledger); err != nil {
+		if restoreErr := s.restartBackend(currentLedger); restoreErr != nil {
+			log.Printf("⚠️  Error restoring ledger backend at ledger %d: %v", currentLedger, restoreErr)
+		}
+		return err
+	}
+
+	// The chain continuity check and the checkpoint policy start over from the new position
+	s.lastLedgerSeq = 0
+	s.lastLedgerHash = xdr.Hash{}
+	s.checkpoints.reset()
+
+	// The backend has already moved, so a failed save doesn't undo the seek: the next checkpoint fixes it
+	if s.checkpointMgr != nil {
+		if err := s.checkpointMgr.Save(s.ctx, ledger-1); err != nil {
+			log.Printf("⚠️  Error saving checkpoint at ledger %d after seek: %v", ledger-1, err)
+		}
+	}
+
+	return nil
+}
+
+// restartBackend rebuilds the backend with an unbounded range from ledger, since a prepared backend can't change range
+func (s *OrchestratorService) restartBackend(ledger uint32) error {
+	if err := s.ledgerBackend.Close(); err != nil {
+		log.Printf("⚠️  Error closing ledger backend: %v", err)
+	}
+	if err := s.ledgerBackend.Start(); err != nil {
+		return fmt.Errorf("error restarting ledger backend: %w", err)
+	}
+	if err := s.ledgerBackend.PrepareRange(s.ctx, &ledger, nil); err != nil {
+		return fmt.Errorf("error preparing ledger range: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/xdr"
+)
+
+// fakeBackendHandler records the ledgers the backend is prepared at
+type fakeBackendHandler struct {
+	prepared []uint32 // Start ledger of every PrepareRange call
+	failAt   uint32   // PrepareRange fails when starting at this ledger
+}
+
+func (h *fakeBackendHandler) PrepareRange(ctx context.Context, start, end *uint32) error {
+	h.prepared = append(h.prepared, *start)
+	if *start == h.failAt {
+		return errors.New("range unavailable")
+	}
+	return nil
+}
+
+func (h *fakeBackendHandler) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	return 0, nil
+}
+
+func (h *fakeBackendHandler) Start() error {
+	return nil
+}
+
+func (h *fakeBackendHandler) Close() error {
+	return nil
+}
+
+func (h *fakeBackendHandler) HandleBackend() (ledgerbackend.LedgerBackend, error) {
+	return &fakeLedgers{}, nil
+}
+
+func (h *fakeBackendHandler) IsAvailable() bool {
+	return true
+}
+
+// memoryCheckpoints records every checkpoint saved
+type memoryCheckpoints struct {
+	saved []uint32
+}
+
+func (c *memoryCheckpoints) Save(ctx context.Context, ledgerSeq uint32) error {
+	c.saved = append(c.saved, ledgerSeq)
+	return nil
+}
+
+func (c *memoryCheckpoints) Load(ctx context.Context) (uint32, error) {
+	if len(c.saved) == 0 {
+		return 0, nil
+	}
+	return c.saved[len(c.saved)-1], nil
+}
+
+// seekService returns an orchestrator processing ledger 501 with the network tip at 1000
+func seekService(backend *fakeBackendHandler, checkpoints *memoryCheckpoints) *OrchestratorService {
+	return &OrchestratorService{
+		ctx:            context.Background(),
+		ledgerBackend:  backend,
+		checkpointMgr:  checkpoints,
+		checkpoints:    checkpointer{everyLedgers: 10, lastSaved: 490},
+		networkTip:     fixedTip(1000, nil),
+		lastLedgerSeq:  500,
+		lastLedgerHash: xdr.Hash{1},
+	}
+}
+
+func TestSeekRejectsLedgerZero(t *testing.T) {
+	s := &OrchestratorService{}
+
+	if _, err := s.Seek(context.Background(), 0); err == nil {
+		t.Fatal("Seek(0) error = nil, want an error")
+	}
+}
+
+func TestSeekNotRunning(t *testing.T) {
+	s := &OrchestratorService{control: make(chan controlCommand), loopDone: make(chan struct{})}
+	close(s.loopDone)
+
+	if _, err := s.Seek(context.Background(), 100); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Seek() error = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	for _, ledger := range []uint32{100, 800, 1001} {
+		backend := &fakeBackendHandler{}
+		checkpoints := &memoryCheckpoints{}
+		s := seekService(backend, checkpoints)
+
+		if err := s.seek(ledger, 501); err != nil {
+			t.Fatalf("seek(%d) error = %v", ledger, err)
+		}
+
+		if !slices.Equal(backend.prepared, []uint32{ledger}) {
+			t.Errorf("seek(%d) prepared the backend at %v", ledger, backend.prepared)
+		}
+		if !slices.Equal(checkpoints.saved, []uint32{ledger - 1}) {
+			t.Errorf("seek(%d) saved checkpoints %v, want [%d]", ledger, checkpoints.saved, ledger-1)
+		}
+
+		// Chain continuity and the checkpoint policy start over at the new position
+		if s.lastLedgerSeq != 0 || s.lastLedgerHash != (xdr.Hash{}) {
+			t.Errorf("seek(%d) kept chain tracking at ledger %d", ledger, s.lastLedgerSeq)
+		}
+		if s.checkpoints.lastSaved != 0 {
+			t.Errorf("seek(%d) kept the checkpoint policy at ledger %d", ledger, s.checkpoints.lastSaved)
+		}
+	}
+}
+
+func TestSeekAheadOfTip(t *testing.T) {
+	backend := &fakeBackendHandler{}
+	checkpoints := &memoryCheckpoints{}
+	s := seekService(backend, checkpoints)
+
+	if err := s.seek(1002, 501); !errors.Is(err, ErrSeekAheadOfTip) {
+		t.Fatalf("seek(1002) error = %v, want ErrSeekAheadOfTip", err)
+	}
+	if len(backend.prepared) != 0 || len(checkpoints.saved) != 0 {
+		t.Errorf("rejected seek prepared the backend at %v and saved checkpoints %v", backend.prepared, checkpoints.saved)
+	}
+	if s.lastLedgerSeq != 500 {
+		t.Errorf("rejected seek reset chain tracking")
+	}
+}
+
+func TestSeekTipUnknown(t *testing.T) {
+	backend := &fakeBackendHandler{}
+	s := seekService(backend, &memoryCheckpoints{})
+	s.networkTip = fixedTip(0, errors.New("rpc unavailable"))
+
+	if err := s.seek(800, 501); err == nil {
+		t.Fatal("seek() error = nil without a network tip")
+	}
+	if len(backend.prepared) != 0 {
+		t.Errorf("seek without a network tip prepared the backend at %v", backend.prepared)
+	}
+}
+
+func TestSeekRestoresOnFailure(t *testing.T) {
+	backend := &fakeBackendHandler{failAt: 800}
+	checkpoints := &memoryCheckpoints{}
+	s := seekService(backend, checkpoints)
+
+	if err := s.seek(800, 501); err == nil {
+		t.Fatal("seek() error = nil, want the backend error")
+	}
+
+	// The backend goes back to the ledger being processed, with nothing else changed
+	if !slices.Equal(backend.prepared, []uint32{800, 501}) {
+		t.Errorf("backend prepared at %v, want [800 501]", backend.prepared)
+	}
+	if len(checkpoints.saved) != 0 {
+		t.Errorf("failed seek saved checkpoints %v", checkpoints.saved)
+	}
+	if s.lastLedgerSeq != 500 || s.checkpoints.lastSaved != 490 {
+		t.Errorf("failed seek reset chain tracking or the checkpoint policy")
+	}
+}
+
+func TestSeekThroughLoop(t *testing.T) {
+	s := seekService(&fakeBackendHandler{}, &memoryCheckpoints{})
+	s.control = make(chan controlCommand)
+	s.loopDone = make(chan struct{})
+
+	// Stands in for the ingestion loop, handling a single command
+	next := make(chan uint32, 1)
+	go func() {
+		paused := false
+		next <- s.handleControl(<-s.control, &paused, 501)
+	}()
+
+	state, err := s.Seek(context.Background(), 800)
+	if err != nil {
+		t.Fatalf("Seek() error = %v", err)
+	}
+	if state.NextLedger != 800 || state.Paused {
+		t.Errorf("Seek() state = %+v, want next ledger 800, not paused", state)
+	}
+	if got := <-next; got != 800 {
+		t.Errorf("loop continues at ledger %d, want 800", got)
+	}
+}
@@ -0,0 +1,218 @@
+package ingest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"indexer/internal/logging"
+	"indexer/internal/metrics"
+	"indexer/internal/service/rpc"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// RetryResult summarizes a retry run over the failed transaction queue
+type RetryResult struct {
+	Attempted    int `json:"attempted"`
+	Resolved     int `json:"resolved"`
+	StillFailing int `json:"still_failing"`
+}
+
+// BackendFactory creates a fresh ledger backend, since a backend serves a single prepared range
+type BackendFactory func() (rpc.LedgerBackendHandlerService, error)
+
+// FailedTransactionRetrier reprocesses queued transactions by fetching their ledger again
+type FailedTransactionRetrier struct {
+	store             FailedTransactionStore
+	newBackend        BackendFactory
+	processors        map[string]Processor
+	networkPassphrase string
+	mu                sync.Mutex // One retry run at a time
+}
+
+// NewFailedTransactionRetrier creates a retrier for the given processors
+func NewFailedTransactionRetrier(store FailedTransactionStore, newBackend BackendFactory, processors []Processor, networkPassphrase string) *FailedTransactionRetrier {
+	byName := make(map[string]Processor, len(processors))
+	for _, processor := range processors {
+		byName[processor.Name()] = processor
+	}
+
+	return &FailedTransactionRetrier{
+		store:             store,
+		newBackend:        newBackend,
+		processors:        byName,
+		networkPassphrase: networkPassphrase,
+	}
+}
+
+// List returns queued transactions
+func (r *FailedTransactionRetrier) List(ctx context.Context, unresolvedOnly bool) ([]FailedTransaction, error) {
+	return r.store.ListFailedTransactions(ctx, unresolvedOnly)
+}
+
+// RetryAll reprocesses every unresolved transaction with the processor that failed on it
+func (r *FailedTransactionRetrier) RetryAll(ctx context.Context) (RetryResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending, err := r.store.ListFailedTransactions(ctx, true)
+	if err != nil {
+		return RetryResult{}, err
+	}
+
+	// Fetch each ledger only once
+	byLedger := make(map[uint32][]FailedTransaction)
+	for _, failed := range pending {
+		byLedger[failed.LedgerSequence] = append(byLedger[failed.LedgerSequence], failed)
+	}
+
+	ledgers := make([]uint32, 0, len(byLedger))
+	for sequence := range byLedger {
+		ledgers = append(ledgers, sequence)
+	}
+	sort.Slice(ledgers, func(i, j int) bool { return ledgers[i] < ledgers[j] })
+
+	var result RetryResult
+	for _, sequence := range ledgers {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if err := r.retryLedger(ctx, sequence, byLedger[sequence], &result); err != nil {
+			return result, err
+		}
+	}
+
+	logging.Printf(ctx, "♻️  Retried failed transactions: %d attempted, %d resolved, %d still failing",
+		result.Attempted, result.Resolved, result.StillFailing)
+
+	return result, nil
+}
+
+// retryLedger fetches one ledger and reprocesses its queued transactions
+func (r *FailedTransactionRetrier) retryLedger(ctx context.Context, sequence uint32, entries []FailedTransaction, result *RetryResult) error {
+	ledger, err := FetchLedger(ctx, r.newBackend, sequence)
+	if err != nil {
+		// The ledger may be temporarily unavailable, keep its entries queued
+		logging.Printf(ctx, "⚠️  Could not fetch ledger %d to retry failed transactions: %v", sequence, err)
+		result.Attempted += len(entries)
+		result.StillFailing += len(entries)
+		return nil
+	}
+
+	txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(r.networkPassphrase, ledger)
+	if err != nil {
+		return fmt.Errorf("error creating transaction reader for ledger %d: %w", sequence, err)
+	}
+	defer txReader.Close()
+
+	byIndex := make(map[uint32][]FailedTransaction)
+	for _, entry := range entries {
+		byIndex[entry.TxIndex] = append(byIndex[entry.TxIndex], entry)
+	}
+
+	for {
+		tx, err := txReader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("error reading transaction: %w", err)
+		}
+
+		for _, entry := range byIndex[tx.Index] {
+			processor, ok := r.processors[entry.Processor]
+			if !ok {
+				continue
+			}
+
+			result.Attempted++
+			entry.Attempts++
+
+			if err := processor.ProcessTransaction(ctx, tx); err != nil {
+				entry.Error = err.Error()
+				result.StillFailing++
+			} else {
+				now := time.Now().UTC()
+				entry.ResolvedAt = &now
+				result.Resolved++
+			}
+
+			if err := r.store.UpdateFailedTransaction(ctx, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FetchLedger reads a single ledger through a dedicated backend
+func FetchLedger(ctx context.Context, newBackend BackendFactory, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	ledgerBackend, err := newBackend()
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+
+	if err := ledgerBackend.Start(); err != nil {
+		return xdr.LedgerCloseMeta{}, fmt.Errorf("error starting ledger backend: %w", err)
+	}
+	defer ledgerBackend.Close()
+
+	if err := ledgerBackend.PrepareRange(ctx, &sequence, &sequence); err != nil {
+		return xdr.LedgerCloseMeta{}, fmt.Errorf("error preparing ledger range: %w", err)
+	}
+
+	backend, err := ledgerBackend.HandleBackend()
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, fmt.Errorf("error getting backend: %w", err)
+	}
+
+	return backend.GetLedger(ctx, sequence)
+}
+
+// recordFailedTransaction queues a transaction a processor failed on
+func (s *OrchestratorService) recordFailedTransaction(processor string, tx ingest.LedgerTransaction, processErr error) {
+	metrics.FailedTransactions.WithLabelValues(processor).Inc()
+
+	if s.failedTxs == nil {
+		return
+	}
+
+	envelope, err := xdr.MarshalBase64(tx.Envelope)
+	if err != nil {
+		log.Printf("⚠️  Could not encode failed transaction envelope: %v", err)
+	}
+
+	failed := FailedTransaction{
+		ID:             newID(),
+		Processor:      processor,
+		LedgerSequence: tx.Ledger.LedgerSequence(),
+		TxIndex:        tx.Index,
+		TxHash:         hex.EncodeToString(tx.Result.TransactionHash[:]),
+		EnvelopeXDR:    envelope,
+		Error:          processErr.Error(),
+		Attempts:       1,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	if err := s.failedTxs.SaveFailedTransaction(s.ctx, failed); err != nil {
+		log.Printf("❌ Error saving failed transaction %s: %v", failed.TxHash, err)
+	}
+}
+
+// newID returns a random identifier for a queue entry
+func newID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
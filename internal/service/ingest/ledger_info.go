@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerInfo summarizes what a processed ledger contained and how long it took
+type LedgerInfo struct {
+	Sequence            uint32    `json:"sequence"`
+	ClosedAt            time.Time `json:"closed_at"`
+	ProcessedAt         time.Time `json:"processed_at"`
+	Transactions        int       `json:"transactions"`
+	FailedTransactions  int       `json:"failed_transactions"` // Failed on the network, not in a processor
+	SorobanTransactions int       `json:"soroban_transactions"`
+	ContractEvents      int       `json:"contract_events"`
+	Deployments         int       `json:"deployments"`     // Contract instances created
+	StorageChanges      int       `json:"storage_changes"` // Contract data entries created, updated, removed or restored
+	ProcessingMs        float64   `json:"processing_ms"`   // From fetched to processed by every processor
+	Lag                 uint32    `json:"lag"`             // Ledgers behind the network tip once processed
+}
+
+// LedgerInfoStore receives the LedgerInfo of every processed ledger
+type LedgerInfoStore interface {
+	SaveLedgerInfo(ctx context.Context, info LedgerInfo) error
+}
+
+// newLedgerInfo starts the summary of a fetched ledger
+func newLedgerInfo(ledger xdr.LedgerCloseMeta) LedgerInfo {
+	return LedgerInfo{
+		Sequence: ledger.LedgerSequence(),
+		ClosedAt: time.Unix(ledger.LedgerCloseTime(), 0).UTC(),
+	}
+}
+
+// addTransaction counts a transaction of the ledger
+func (i *LedgerInfo) addTransaction(tx ingest.LedgerTransaction) {
+	i.Transactions++
+	if !tx.Successful() {
+		i.FailedTransactions++
+	}
+
+	if !tx.IsSorobanTx() {
+		return
+	}
+	i.SorobanTransactions++
+
+	if events, err := tx.GetContractEvents(); err == nil {
+		i.ContractEvents += len(events)
+	}
+
+	changes, err := tx.GetChanges()
+	if err != nil {
+		return
+	}
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		i.StorageChanges++
+
+		if change.Pre == nil && change.Post != nil &&
+			change.Post.Data.MustContractData().Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance {
+			i.Deployments++
+		}
+	}
+}
@@ -2,21 +2,76 @@ package ingest
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"indexer/internal/metrics"
 	"indexer/internal/service/rpc"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/stellar/go/ingest"
-	"github.com/stellar/go/network"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/xdr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrChainReset is returned when the ledger chain served by the backend is no longer
+// the one previously ingested (e.g. a testnet reset). Ingestion refuses to continue
+// so data from two different chains is never mixed.
+var ErrChainReset = errors.New("ledger chain reset detected")
+
+// ErrNoNetworkPassphrase is returned when ingestion starts without a network passphrase
+var ErrNoNetworkPassphrase = errors.New("network passphrase is required")
+
+// ErrTxTimeout is returned when a processor does not finish a transaction within the configured timeout
+var ErrTxTimeout = errors.New("transaction processing timed out")
+
+// ErrProcessorBusy is returned for transactions skipped while a processor's timed out call is still running
+var ErrProcessorBusy = errors.New("processor is still running a timed out call")
+
+// tracer creates the spans of the ingestion path
+var tracer = otel.Tracer("indexer/ingest")
+
+// flushTimeout bounds how long Stop waits for processors to flush their state
+const flushTimeout = 10 * time.Second
+
 // OrchestratorService coordinates the ingestion of ledgers from the Stellar network
 type OrchestratorService struct {
-	ledgerBackend rpc.LedgerBackendHandlerService
-	processors    []Processor
-	checkpointMgr CheckpointStore
+	ledgerBackend     rpc.LedgerBackendHandlerService
+	processors        []Processor
+	networkPassphrase string
+	checkpointMgr     CheckpointStore
+	checkpoints       checkpointer
+	failedTxs         FailedTransactionStore
+	freshness         *metrics.FreshnessTracker
+	priorityGate      *PriorityGate
+	txTimeout         time.Duration
+	timedOut          map[string]<-chan error // Calls still running after their timeout, by processor name
+	retryPolicies     map[ErrorClass]RetryPolicy
+	ledgerInfo        LedgerInfoStore
+	networkTip        NetworkTipFunc
+
+	// Chain continuity tracking
+	lastLedgerSeq  uint32
+	lastLedgerHash xdr.Hash
+
+	progress progressTracker
+
+	// Ledger prefetching, owned by the loop goroutine
+	prefetchDepth int
+	prefetchEnd   uint32 // Last ledger of a bounded range, 0 when live (prefetch up to the network tip)
+	prefetcher    *prefetcher
+
+	// Pause, resume and seek commands for the live loop
+	control  chan controlCommand
+	loopDone chan struct{}
+	running  atomic.Bool // The live loop is running on a prepared backend
 
 	// Lifecycle control
 	ctx    context.Context
@@ -25,12 +80,28 @@ type OrchestratorService struct {
 }
 
 // NewIngestService creates a new orchestrator service for ledger ingestion
-func NewIngestService(ledgerBackend rpc.LedgerBackendHandlerService, processors []Processor) *OrchestratorService {
+func NewIngestService(ledgerBackend rpc.LedgerBackendHandlerService, processors []Processor, opts Options) *OrchestratorService {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &OrchestratorService{
-		ledgerBackend: ledgerBackend,
-		processors:    processors,
+		ledgerBackend:     ledgerBackend,
+		processors:        processors,
+		networkPassphrase: opts.NetworkPassphrase,
+		checkpointMgr:     opts.CheckpointStore,
+		checkpoints: checkpointer{
+			everyLedgers: opts.CheckpointEvery,
+			interval:     opts.CheckpointInterval,
+		},
+		failedTxs:     opts.FailedTransactions,
+		freshness:     opts.Freshness,
+		priorityGate:  opts.PriorityGate,
+		txTimeout:     opts.TxTimeout,
+		retryPolicies: opts.RetryPolicies,
+		ledgerInfo:    opts.LedgerInfo,
+		networkTip:    opts.NetworkTip,
+		prefetchDepth: opts.Prefetch,
+		control:       make(chan controlCommand),
+		loopDone:      make(chan struct{}),
 		ctx:           ctx,
 		cancel:        cancel,
 	}
@@ -38,6 +109,10 @@ func NewIngestService(ledgerBackend rpc.LedgerBackendHandlerService, processors
 
 // Start begins the ledger ingestion process from the specified starting ledger
 func (s *OrchestratorService) StartUnboundedRange(startLedger uint32) error {
+	if s.networkPassphrase == "" {
+		return ErrNoNetworkPassphrase
+	}
+
 	log.Printf("🚀 Starting ingestion from ledger %d", startLedger)
 
 	// Prepare unbounded range for continuous streaming
@@ -45,7 +120,16 @@ func (s *OrchestratorService) StartUnboundedRange(startLedger uint32) error {
 		return fmt.Errorf("error preparing ledger range: %w", err)
 	}
 
+	// Refuse to start past the network tip, which happens after a chain reset
+	if err := s.detectChainReset(startLedger); err != nil {
+		return err
+	}
+
+	// The first ledger must extend the checkpointed one, which catches a reset while stopped
+	s.restoreLastLedgerHash(startLedger)
+
 	s.wg.Add(1)
+	s.running.Store(true)
 	go s.ingestLoop(startLedger)
 
 	return nil
@@ -54,10 +138,14 @@ func (s *OrchestratorService) StartUnboundedRange(startLedger uint32) error {
 // ingestLoop is the main ingestion loop that continuously processes ledgers
 func (s *OrchestratorService) ingestLoop(startLedger uint32) {
 	defer s.wg.Done()
+	defer close(s.loopDone)
+	defer s.running.Store(false)
+	defer s.stopPrefetch()
+	defer s.reportLiveIdle()
 
 	currentLedger := startLedger
-	consecutiveErrors := 0
-	maxConsecutiveErrors := 5
+	retries := newRetrier(s.retryPolicies)
+	paused := false
 
 	ticker := time.NewTicker(2 * time.Second) // Poll every 2 seconds
 	defer ticker.Stop()
@@ -68,51 +156,177 @@ func (s *OrchestratorService) ingestLoop(startLedger uint32) {
 			log.Println("⏹️  Stopping ingestion...")
 			return
 
+		case cmd := <-s.control:
+			currentLedger = s.handleControl(cmd, &paused, currentLedger)
+			retries.reset()
+
 		case <-ticker.C:
+			if paused {
+				continue
+			}
+
 			// Attempt to process the next ledger
 			if err := s.processLedger(currentLedger); err != nil {
-				consecutiveErrors++
-				log.Printf("❌ Error processing ledger %d (attempt %d/%d): %v",
-					currentLedger, consecutiveErrors, maxConsecutiveErrors, err)
+				if errors.Is(err, ErrChainReset) {
+					log.Printf("🔴 %v, stopping ingestion", err)
+					return
+				}
+
+				// A ledger that keeps failing may no longer exist on the network
+				if resetErr := s.detectChainReset(currentLedger); resetErr != nil {
+					log.Printf("🔴 %v, stopping ingestion", resetErr)
+					return
+				}
+
+				retry := retries.fail(err)
+				log.Printf("❌ Error processing ledger %d (%s, attempt %d/%d): %v",
+					currentLedger, retry.Class, retry.Attempt, retry.MaxRetries, err)
 
-				if consecutiveErrors >= maxConsecutiveErrors {
+				if retry.GiveUp {
 					log.Printf("🔴 Too many consecutive errors, stopping...")
 					return
 				}
 
-				// Exponential backoff
-				time.Sleep(time.Duration(consecutiveErrors) * time.Second)
+				// Exponential backoff with full jitter
+				if !s.sleep(retry.Wait) {
+					log.Println("⏹️  Stopping ingestion...")
+					return
+				}
 				continue
 			}
 
 			// Success - reset counter and advance
-			consecutiveErrors = 0
+			retries.reset()
 			log.Printf("✅ Ledger %d processed successfully", currentLedger)
+			s.maybeCheckpoint(currentLedger)
+			s.reportLiveLag(currentLedger)
 			currentLedger++
 		}
 	}
 }
 
+// RunBoundedRange processes every ledger in [startLedger, endLedger] and blocks until done.
+// A final checkpoint is written once the whole range has been processed.
+func (s *OrchestratorService) RunBoundedRange(startLedger, endLedger uint32) error {
+	if endLedger < startLedger {
+		return fmt.Errorf("invalid range: end ledger %d is before start ledger %d", endLedger, startLedger)
+	}
+	if s.networkPassphrase == "" {
+		return ErrNoNetworkPassphrase
+	}
+
+	log.Printf("🚀 Starting backfill of ledgers %d-%d", startLedger, endLedger)
+
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	s.prefetchEnd = endLedger
+	defer s.stopPrefetch()
+
+	// Prepare bounded range, no polling needed since the ledgers already exist
+	if err := s.ledgerBackend.PrepareRange(s.ctx, &startLedger, &endLedger); err != nil {
+		return fmt.Errorf("error preparing ledger range: %w", err)
+	}
+
+	currentLedger := startLedger
+	retries := newRetrier(s.retryPolicies)
+
+	for currentLedger <= endLedger {
+		select {
+		case <-s.ctx.Done():
+			log.Printf("⏹️  Backfill interrupted at ledger %d", currentLedger)
+			return s.ctx.Err()
+		default:
+		}
+
+		// Yield to the live lane while it is catching up
+		if s.priorityGate != nil {
+			if err := s.priorityGate.Wait(s.ctx); err != nil {
+				log.Printf("⏹️  Backfill interrupted at ledger %d", currentLedger)
+				return err
+			}
+		}
+
+		if err := s.processLedger(currentLedger); err != nil {
+			if errors.Is(err, ErrChainReset) {
+				return err
+			}
+
+			retry := retries.fail(err)
+			log.Printf("❌ Error processing ledger %d (%s, attempt %d/%d): %v",
+				currentLedger, retry.Class, retry.Attempt, retry.MaxRetries, err)
+
+			if retry.GiveUp {
+				return fmt.Errorf("too many consecutive errors at ledger %d: %w", currentLedger, err)
+			}
+
+			if !s.sleep(retry.Wait) {
+				log.Printf("⏹️  Backfill interrupted at ledger %d", currentLedger)
+				return s.ctx.Err()
+			}
+			continue
+		}
+
+		retries.reset()
+		s.maybeCheckpoint(currentLedger)
+		currentLedger++
+	}
+
+	// Processors hand off what they accumulated before the range counts as done
+	flushCtx, cancel := context.WithTimeout(s.ctx, flushTimeout)
+	defer cancel()
+	if err := s.flushProcessors(flushCtx); err != nil {
+		return fmt.Errorf("error flushing processors: %w", err)
+	}
+
+	// Write the final checkpoint for this range
+	if s.checkpointMgr != nil {
+		if err := s.checkpointMgr.Save(s.ctx, endLedger); err != nil {
+			return fmt.Errorf("error saving final checkpoint: %w", err)
+		}
+	}
+
+	log.Printf("✅ Backfill of ledgers %d-%d completed", startLedger, endLedger)
+
+	return nil
+}
+
 // processLedger processes an individual ledger and its transactions
-func (s *OrchestratorService) processLedger(sequence uint32) error {
+func (s *OrchestratorService) processLedger(sequence uint32) (err error) {
+	ctx, span := tracer.Start(s.ctx, "ingest.ProcessLedger",
+		trace.WithAttributes(attribute.Int64("ledger.sequence", int64(sequence))))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	// Get the backend instance
 	backend, err := s.ledgerBackend.HandleBackend()
 	if err != nil {
 		return fmt.Errorf("error getting backend: %w", err)
 	}
 
-	// Fetch ledger from backend
-	ledger, err := backend.GetLedger(s.ctx, sequence)
+	// Fetch ledger from backend (or the prefetcher)
+	ledger, err := s.getLedger(ctx, backend, sequence)
 	if err != nil {
 		return fmt.Errorf("error fetching ledger: %w", err)
 	}
 
-	// Create transaction reader
-	txReader, err := ingest.NewLedgerTransactionReader(
-		s.ctx,
-		backend,
-		network.TestNetworkPassphrase,
-		sequence,
+	// Make sure the ledger extends the chain we have been ingesting
+	if err := s.verifyChainContinuity(ledger); err != nil {
+		return err
+	}
+
+	started := time.Now()
+	info := newLedgerInfo(ledger)
+
+	// Create transaction reader from the fetched ledger, so the backend is not asked for it again
+	txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(
+		s.networkPassphrase,
+		ledger,
 	)
 	if err != nil {
 		return fmt.Errorf("error creating transaction reader: %w", err)
@@ -121,7 +335,7 @@ func (s *OrchestratorService) processLedger(sequence uint32) error {
 
 	// Process the ledger with each processor
 	for _, processor := range s.processors {
-		if err := processor.ProcessLedger(s.ctx, ledger); err != nil {
+		if err := s.runLedgerProcessor(ctx, processor, ledger); err != nil {
 			log.Printf("⚠️  Processor %s failed on ledger: %v", processor.Name(), err)
 			// Continue with other processors
 		}
@@ -136,23 +350,296 @@ func (s *OrchestratorService) processLedger(sequence uint32) error {
 			}
 			return fmt.Errorf("error reading transaction: %w", err)
 		}
+		info.addTransaction(tx)
 
 		// Process transaction with each processor
 		for _, processor := range s.processors {
-			if err := processor.ProcessTransaction(s.ctx, tx); err != nil {
+			if err := s.runTxProcessor(ctx, processor, tx); err != nil {
+				// Interrupted by shutdown, the ledger will be processed again on restart
+				if s.ctx.Err() != nil {
+					return s.ctx.Err()
+				}
 				log.Printf("⚠️  Processor %s failed on transaction: %v", processor.Name(), err)
+				s.recordFailedTransaction(processor.Name(), tx, err)
 				// Continue with other processors
 			}
 		}
 	}
 
+	s.lastLedgerSeq = sequence
+	s.lastLedgerHash = ledger.LedgerHash()
+	s.progress.recordLedger(sequence, time.Unix(ledger.LedgerCloseTime(), 0))
+
+	// Record close-to-indexed latency for the freshness SLO
+	if s.freshness != nil {
+		s.freshness.ObserveLedger(sequence, time.Unix(ledger.LedgerCloseTime(), 0))
+	}
+
+	if s.ledgerInfo != nil {
+		info.ProcessingMs = float64(time.Since(started).Microseconds()) / 1000
+		info.ProcessedAt = time.Now().UTC()
+		info.Lag = s.progress.snapshot().Lag
+		if err := s.ledgerInfo.SaveLedgerInfo(ctx, info); err != nil {
+			log.Printf("⚠️  Error saving ledger info for %d: %v", sequence, err)
+		}
+	}
+
+	return nil
+}
+
+// fetchLedger reads a ledger from the backend inside its own span
+func (s *OrchestratorService) fetchLedger(ctx context.Context, backend ledgerbackend.LedgerBackend, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	ctx, span := tracer.Start(ctx, "backend.GetLedger")
+	defer span.End()
+
+	ledger, err := backend.GetLedger(ctx, sequence)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return ledger, err
+}
+
+// runLedgerProcessor runs a processor on a ledger, recording its duration, errors and span
+func (s *OrchestratorService) runLedgerProcessor(ctx context.Context, processor Processor, ledger xdr.LedgerCloseMeta) error {
+	ctx, span := tracer.Start(ctx, "processor.ProcessLedger",
+		trace.WithAttributes(attribute.String("processor", processor.Name())))
+	defer span.End()
+
+	if s.processorBusy(processor) {
+		return ErrProcessorBusy
+	}
+
+	started := time.Now()
+	err := processor.ProcessLedger(ctx, ledger)
+	metrics.ProcessorDuration.WithLabelValues(processor.Name(), "ledger").Observe(time.Since(started).Seconds())
+
+	if err != nil {
+		metrics.ProcessorErrors.WithLabelValues(processor.Name(), "ledger").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// runTxProcessor runs a processor on a transaction, recording its duration, errors and span
+func (s *OrchestratorService) runTxProcessor(ctx context.Context, processor Processor, tx ingest.LedgerTransaction) error {
+	ctx, span := tracer.Start(ctx, "processor.ProcessTransaction", trace.WithAttributes(
+		attribute.String("processor", processor.Name()),
+		attribute.String("tx.hash", hex.EncodeToString(tx.Result.TransactionHash[:])),
+	))
+	defer span.End()
+
+	started := time.Now()
+	err := s.processTxWithTimeout(ctx, processor, tx)
+	metrics.ProcessorDuration.WithLabelValues(processor.Name(), "transaction").Observe(time.Since(started).Seconds())
+
+	if err != nil {
+		metrics.ProcessorErrors.WithLabelValues(processor.Name(), "transaction").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// processTxWithTimeout runs the processor under the per-transaction deadline. A processor that ignores
+// the cancelled context is left running so one hung call cannot stall the stream, but it is never
+// called again until that call returns: its transactions fail with ErrProcessorBusy meanwhile.
+func (s *OrchestratorService) processTxWithTimeout(ctx context.Context, processor Processor, tx ingest.LedgerTransaction) error {
+	if s.txTimeout <= 0 {
+		return processor.ProcessTransaction(ctx, tx)
+	}
+
+	if s.processorBusy(processor) {
+		return ErrProcessorBusy
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.txTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- processor.ProcessTransaction(ctx, tx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			metrics.ProcessorTimeouts.WithLabelValues(processor.Name()).Inc()
+			return fmt.Errorf("%w after %s: %v", ErrTxTimeout, s.txTimeout, err)
+		}
+		return err
+	case <-ctx.Done():
+		if s.ctx.Err() != nil {
+			// Shutting down, not a timeout
+			return s.ctx.Err()
+		}
+		metrics.ProcessorTimeouts.WithLabelValues(processor.Name()).Inc()
+		if s.timedOut == nil {
+			s.timedOut = make(map[string]<-chan error)
+		}
+		s.timedOut[processor.Name()] = done
+		return fmt.Errorf("%w after %s", ErrTxTimeout, s.txTimeout)
+	}
+}
+
+// reportLiveIdle releases backfill lanes waiting on the priority gate when the live lane stops or pauses
+func (s *OrchestratorService) reportLiveIdle() {
+	if s.priorityGate != nil {
+		s.priorityGate.ReportLiveIdle()
+	}
+}
+
+// processorBusy reports whether a timed out call of the processor is still running
+func (s *OrchestratorService) processorBusy(processor Processor) bool {
+	pending, ok := s.timedOut[processor.Name()]
+	if !ok {
+		return false
+	}
+
+	select {
+	case <-pending:
+		delete(s.timedOut, processor.Name())
+		return false
+	default:
+		return true
+	}
+}
+
+// reportLiveLag records the network tip and tells the priority gate how far the live lane is behind it
+func (s *OrchestratorService) reportLiveLag(processedLedger uint32) {
+	latest, err := s.latestNetworkLedger(s.ctx)
+	if err != nil {
+		return
+	}
+	s.progress.recordNetworkLedger(latest)
+
+	if s.priorityGate == nil {
+		return
+	}
+
+	var lag uint32
+	if latest > processedLedger {
+		lag = latest - processedLedger
+	}
+	s.priorityGate.ReportLiveLag(lag)
+}
+
+// latestNetworkLedger returns the network tip. Backends such as RPC only report the end of
+// what they serve (the RPC buffer), so the configured NetworkTip source is preferred.
+func (s *OrchestratorService) latestNetworkLedger(ctx context.Context) (uint32, error) {
+	if s.networkTip != nil {
+		return s.networkTip(ctx)
+	}
+	return s.ledgerBackend.GetLatestLedgerSequence(ctx)
+}
+
+// verifyChainContinuity checks that the ledger's previous hash matches the hash of the
+// last ledger processed, which changes when the network has been reset
+func (s *OrchestratorService) verifyChainContinuity(ledger xdr.LedgerCloseMeta) error {
+	sequence := ledger.LedgerSequence()
+
+	// Nothing to compare against until a ledger has been processed
+	if s.lastLedgerSeq == 0 || sequence != s.lastLedgerSeq+1 {
+		return nil
+	}
+
+	if previous := ledger.PreviousLedgerHash(); previous != s.lastLedgerHash {
+		return fmt.Errorf("%w: ledger %d previous hash %s does not match ledger %d hash %s",
+			ErrChainReset, sequence, previous.HexString(), s.lastLedgerSeq, s.lastLedgerHash.HexString())
+	}
+
+	return nil
+}
+
+// detectChainReset reports a reset when the network tip is behind the ledger we expect
+// to process next, meaning the sequence numbering has started over
+func (s *OrchestratorService) detectChainReset(expectedLedger uint32) error {
+	latest, err := s.latestNetworkLedger(s.ctx)
+	if err != nil {
+		// Can't tell, let the regular error handling decide
+		return nil
+	}
+
+	if latest+1 < expectedLedger {
+		return fmt.Errorf("%w: network tip is ledger %d but ingestion expects ledger %d",
+			ErrChainReset, latest, expectedLedger)
+	}
+
 	return nil
 }
 
+// flush lets Flushable processors hand off accumulated state, then saves the last processed ledger
+// as the checkpoint. It runs after the loops have exited, so no ledger is in progress.
+func (s *OrchestratorService) flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	if err := s.flushProcessors(ctx); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+
+	if s.checkpointMgr != nil && s.lastLedgerSeq != 0 {
+		if err := s.saveCheckpoint(ctx, s.lastLedgerSeq); err != nil {
+			log.Printf("⚠️  Error saving final checkpoint: %v", err)
+			return
+		}
+		log.Printf("💾 Final checkpoint saved at ledger %d", s.lastLedgerSeq)
+	}
+}
+
+// flushProcessors lets Flushable processors hand off accumulated state, returning every failure
+func (s *OrchestratorService) flushProcessors(ctx context.Context) error {
+	var errs []error
+	for _, processor := range s.processors {
+		flushable, ok := processor.(Flushable)
+		if !ok {
+			continue
+		}
+
+		// Never flush while a timed out call is still inside the processor
+		if pending, ok := s.timedOut[processor.Name()]; ok {
+			select {
+			case <-pending:
+				delete(s.timedOut, processor.Name())
+			case <-ctx.Done():
+				errs = append(errs, fmt.Errorf("processor %s is still running a timed out call, not flushed", processor.Name()))
+				continue
+			}
+		}
+
+		if err := flushable.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("processor %s failed to flush: %w", processor.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Progress returns the current ingestion position, lag and rate. Safe to call at any time.
+func (s *OrchestratorService) Progress() Progress {
+	return s.progress.snapshot()
+}
+
+// Running reports whether the live loop is running on a prepared backend
+func (s *OrchestratorService) Running() bool {
+	return s.running.Load()
+}
+
+// LastLedger returns the last ledger processed successfully (0 if none).
+// Only safe to call once the service has stopped.
+func (s *OrchestratorService) LastLedger() uint32 {
+	return s.lastLedgerSeq
+}
+
 // Stop gracefully stops the ingestion service
 func (s *OrchestratorService) Stop() {
 	log.Println("🛑 Requesting ingestion shutdown...")
 	s.cancel()
 	s.wg.Wait()
+
+	s.flush()
 	log.Println("✅ Ingestion stopped")
 }
@@ -0,0 +1,98 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+// testLedger builds the close meta of a ledger with the given sequence and previous ledger hash
+func testLedger(sequence uint32, previous xdr.Hash) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerSeq:          xdr.Uint32(sequence),
+					PreviousLedgerHash: previous,
+				},
+			},
+		},
+	}
+}
+
+// fixedTip returns a network tip source that always answers tip, or err when set
+func fixedTip(tip uint32, err error) NetworkTipFunc {
+	return func(ctx context.Context) (uint32, error) {
+		return tip, err
+	}
+}
+
+func TestVerifyChainContinuity(t *testing.T) {
+	hash := xdr.Hash{1}
+	other := xdr.Hash{2}
+
+	tests := []struct {
+		name    string
+		lastSeq uint32
+		ledger  xdr.LedgerCloseMeta
+		wantErr bool
+	}{
+		{"nothing processed yet", 0, testLedger(100, other), false},
+		{"next ledger with matching hash", 99, testLedger(100, hash), false},
+		{"next ledger with another hash", 99, testLedger(100, other), true},
+		{"non-consecutive ledger is not compared", 90, testLedger(100, other), false},
+		{"same ledger again is not compared", 100, testLedger(100, other), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &OrchestratorService{lastLedgerSeq: tt.lastSeq, lastLedgerHash: hash}
+
+			err := s.verifyChainContinuity(tt.ledger)
+			if tt.wantErr {
+				if !errors.Is(err, ErrChainReset) {
+					t.Fatalf("verifyChainContinuity() error = %v, want ErrChainReset", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifyChainContinuity() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDetectChainReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		tip      uint32
+		tipErr   error
+		expected uint32
+		wantErr  bool
+	}{
+		{"tip ahead", 200, nil, 100, false},
+		{"waiting for the next ledger", 99, nil, 100, false},
+		{"tip behind the expected ledger", 10, nil, 100, true},
+		{"tip unknown", 0, errors.New("rpc unavailable"), 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &OrchestratorService{ctx: context.Background(), networkTip: fixedTip(tt.tip, tt.tipErr)}
+
+			err := s.detectChainReset(tt.expected)
+			if tt.wantErr {
+				if !errors.Is(err, ErrChainReset) {
+					t.Fatalf("detectChainReset(%d) error = %v, want ErrChainReset", tt.expected, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectChainReset(%d) error = %v", tt.expected, err)
+			}
+		})
+	}
+}
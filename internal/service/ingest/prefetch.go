@@ -0,0 +1,135 @@
+package ingest
+
+import (
+	"context"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/xdr"
+)
+
+// prefetched is a ledger fetched ahead of processing
+type prefetched struct {
+	ledger xdr.LedgerCloseMeta
+	err    error
+}
+
+// prefetcher keeps the next ledgers fetched in the background so fetching overlaps with processing
+type prefetcher struct {
+	next    uint32 // Sequence of the next ledger delivered by results
+	results chan prefetched
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// startPrefetch fetches ledgers start to end, keeping up to depth ready
+func (s *OrchestratorService) startPrefetch(backend ledgerbackend.LedgerBackend, start, end uint32, depth int) *prefetcher {
+	ctx, cancel := context.WithCancel(s.ctx)
+	p := &prefetcher{
+		next:    start,
+		results: make(chan prefetched, depth),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.done)
+
+		for sequence := start; sequence <= end; sequence++ {
+			ledger, err := s.fetchLedger(ctx, backend, sequence)
+
+			select {
+			case p.results <- prefetched{ledger: ledger, err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			// The consumer restarts prefetching after a failure
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+// get returns the ledger with the given sequence if it is the next one prefetched
+func (p *prefetcher) get(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
+	if sequence != p.next {
+		return xdr.LedgerCloseMeta{}, false, nil
+	}
+
+	select {
+	case result := <-p.results:
+		p.next++
+		return result.ledger, true, result.err
+	case <-p.done:
+		// Finished, possibly with its last result still buffered
+		select {
+		case result := <-p.results:
+			p.next++
+			return result.ledger, true, result.err
+		default:
+			return xdr.LedgerCloseMeta{}, false, nil
+		}
+	case <-ctx.Done():
+		return xdr.LedgerCloseMeta{}, true, ctx.Err()
+	}
+}
+
+// stop cancels prefetching and waits for the pending fetch to return
+func (p *prefetcher) stop() {
+	p.cancel()
+	<-p.done
+}
+
+// getLedger returns a ledger, from the prefetcher when enabled. The prefetcher is
+// (re)started whenever the requested sequence is not the next one it holds.
+func (s *OrchestratorService) getLedger(ctx context.Context, backend ledgerbackend.LedgerBackend, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	if s.prefetchDepth <= 0 {
+		return s.fetchLedger(ctx, backend, sequence)
+	}
+
+	if s.prefetcher != nil {
+		ledger, ok, err := s.prefetcher.get(ctx, sequence)
+		if ok && err == nil {
+			return ledger, nil
+		}
+		s.stopPrefetch()
+		if ok {
+			return ledger, err
+		}
+	}
+
+	end := s.prefetchEnd
+	if end == 0 {
+		// Live: only prefetch ledgers that already exist. A backend waiting for a
+		// future ledger holds its lock and would block every other call.
+		tip, err := s.latestNetworkLedger(ctx)
+		if err != nil || tip <= sequence {
+			return s.fetchLedger(ctx, backend, sequence)
+		}
+		end = tip
+	}
+
+	s.prefetcher = s.startPrefetch(backend, sequence, end, s.prefetchDepth)
+
+	ledger, ok, err := s.prefetcher.get(ctx, sequence)
+	if !ok {
+		// Past the end of the range, nothing to prefetch
+		s.stopPrefetch()
+		return s.fetchLedger(ctx, backend, sequence)
+	}
+	if err != nil {
+		s.stopPrefetch()
+	}
+	return ledger, err
+}
+
+// stopPrefetch stops the prefetcher, if any, so nothing else reads from the backend
+func (s *OrchestratorService) stopPrefetch() {
+	if s.prefetcher != nil {
+		s.prefetcher.stop()
+		s.prefetcher = nil
+	}
+}
@@ -0,0 +1,153 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/xdr"
+)
+
+// fakeLedgers is a ledger backend serving every ledger, except those set to fail
+type fakeLedgers struct {
+	mu       sync.Mutex
+	failures map[uint32]int // Remaining failures by sequence
+	highest  uint32         // Highest sequence requested
+}
+
+func (b *fakeLedgers) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.highest = max(b.highest, sequence)
+	if b.failures[sequence] > 0 {
+		b.failures[sequence]--
+		return xdr.LedgerCloseMeta{}, errors.New("ledger unavailable")
+	}
+	return testLedger(sequence, xdr.Hash{}), nil
+}
+
+func (b *fakeLedgers) requestedUpTo() uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.highest
+}
+
+func (b *fakeLedgers) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	return 0, nil
+}
+
+func (b *fakeLedgers) PrepareRange(ctx context.Context, r ledgerbackend.Range) error {
+	return nil
+}
+
+func (b *fakeLedgers) IsPrepared(ctx context.Context, r ledgerbackend.Range) (bool, error) {
+	return true, nil
+}
+
+func (b *fakeLedgers) Close() error {
+	return nil
+}
+
+// expectLedger fetches a ledger through getLedger and checks its sequence
+func expectLedger(t *testing.T, s *OrchestratorService, backend ledgerbackend.LedgerBackend, sequence uint32) {
+	t.Helper()
+
+	ledger, err := s.getLedger(context.Background(), backend, sequence)
+	if err != nil {
+		t.Fatalf("getLedger(%d) error = %v", sequence, err)
+	}
+	if got := ledger.LedgerSequence(); got != sequence {
+		t.Fatalf("getLedger(%d) returned ledger %d", sequence, got)
+	}
+}
+
+func TestGetLedgerWithoutPrefetch(t *testing.T) {
+	backend := &fakeLedgers{}
+	s := &OrchestratorService{ctx: context.Background()}
+
+	expectLedger(t, s, backend, 100)
+	if s.prefetcher != nil {
+		t.Error("prefetcher started with prefetch disabled")
+	}
+}
+
+func TestGetLedgerPrefetchesRange(t *testing.T) {
+	backend := &fakeLedgers{}
+	s := &OrchestratorService{ctx: context.Background(), prefetchDepth: 2, prefetchEnd: 110}
+	defer s.stopPrefetch()
+
+	for sequence := uint32(100); sequence <= 110; sequence++ {
+		expectLedger(t, s, backend, sequence)
+	}
+
+	// Past the end of the range the ledger is fetched directly
+	expectLedger(t, s, backend, 111)
+	if s.prefetcher != nil {
+		t.Error("prefetcher still running past the end of the range")
+	}
+}
+
+func TestGetLedgerRestartsOnJump(t *testing.T) {
+	backend := &fakeLedgers{}
+	s := &OrchestratorService{ctx: context.Background(), prefetchDepth: 3, prefetchEnd: 200}
+	defer s.stopPrefetch()
+
+	expectLedger(t, s, backend, 100)
+	expectLedger(t, s, backend, 150)
+	expectLedger(t, s, backend, 151)
+	expectLedger(t, s, backend, 120)
+}
+
+func TestGetLedgerPrefetchError(t *testing.T) {
+	backend := &fakeLedgers{failures: map[uint32]int{102: 1}}
+	s := &OrchestratorService{ctx: context.Background(), prefetchDepth: 2, prefetchEnd: 110}
+	defer s.stopPrefetch()
+
+	expectLedger(t, s, backend, 100)
+	expectLedger(t, s, backend, 101)
+
+	if _, err := s.getLedger(context.Background(), backend, 102); err == nil {
+		t.Fatal("getLedger(102) error = nil, want the backend error")
+	}
+	if s.prefetcher != nil {
+		t.Error("prefetcher still running after an error")
+	}
+
+	// The retry starts prefetching again from the failed ledger
+	expectLedger(t, s, backend, 102)
+	expectLedger(t, s, backend, 103)
+}
+
+func TestGetLedgerLiveBoundedByTip(t *testing.T) {
+	backend := &fakeLedgers{}
+	s := &OrchestratorService{ctx: context.Background(), prefetchDepth: 5, networkTip: fixedTip(103, nil)}
+	defer s.stopPrefetch()
+
+	for sequence := uint32(100); sequence <= 103; sequence++ {
+		expectLedger(t, s, backend, sequence)
+	}
+	s.stopPrefetch()
+
+	if got := backend.requestedUpTo(); got != 103 {
+		t.Fatalf("prefetched up to ledger %d, want the tip 103", got)
+	}
+
+	// At the tip the next ledger is waited for directly, without a prefetcher
+	expectLedger(t, s, backend, 104)
+	if s.prefetcher != nil {
+		t.Error("prefetcher started at the network tip")
+	}
+}
+
+func TestGetLedgerLiveTipUnknown(t *testing.T) {
+	backend := &fakeLedgers{}
+	s := &OrchestratorService{ctx: context.Background(), prefetchDepth: 5, networkTip: fixedTip(0, errors.New("rpc unavailable"))}
+
+	expectLedger(t, s, backend, 100)
+	if s.prefetcher != nil {
+		t.Error("prefetcher started without a network tip")
+	}
+}
@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+)
+
+// PriorityGate lets the live lane take precedence over backfill work. The live lane reports
+// how far it is behind the network tip; backfill lanes wait at the gate while it is lagging.
+type PriorityGate struct {
+	maxLiveLag uint32
+
+	mu         sync.Mutex
+	liveBehind bool
+	changed    chan struct{}
+}
+
+// NewPriorityGate creates a gate that holds backfill work while the live lane is more than maxLiveLag ledgers behind
+func NewPriorityGate(maxLiveLag uint32) *PriorityGate {
+	return &PriorityGate{
+		maxLiveLag: maxLiveLag,
+		changed:    make(chan struct{}),
+	}
+}
+
+// ReportLiveLag records the current distance between the live lane and the network tip
+func (g *PriorityGate) ReportLiveLag(lag uint32) {
+	g.setLiveBehind(lag > g.maxLiveLag)
+}
+
+// ReportLiveIdle opens the gate while the live lane is not ingesting (paused or stopped),
+// since it has no use for the capacity backfill would leave idle
+func (g *PriorityGate) ReportLiveIdle() {
+	g.setLiveBehind(false)
+}
+
+// setLiveBehind updates the state, waking up waiters when it changes
+func (g *PriorityGate) setLiveBehind(behind bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if behind == g.liveBehind {
+		return
+	}
+
+	g.liveBehind = behind
+
+	// Wake up everyone waiting on the previous state
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// Wait blocks while the live lane is behind, returning early if ctx is cancelled
+func (g *PriorityGate) Wait(ctx context.Context) error {
+	for {
+		g.mu.Lock()
+		behind, changed := g.liveBehind, g.changed
+		g.mu.Unlock()
+
+		if !behind {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitAsync runs gate.Wait in the background and returns its result channel
+func waitAsync(ctx context.Context, gate *PriorityGate) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- gate.Wait(ctx) }()
+	return done
+}
+
+func expectBlocked(t *testing.T, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		t.Fatalf("Wait returned %v while the live lane is behind", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func expectReleased(t *testing.T, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Wait = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait still blocked")
+	}
+}
+
+func TestPriorityGateOpenUntilLiveLags(t *testing.T) {
+	gate := NewPriorityGate(10)
+	expectReleased(t, waitAsync(context.Background(), gate))
+
+	gate.ReportLiveLag(10)
+	expectReleased(t, waitAsync(context.Background(), gate))
+}
+
+func TestPriorityGateHoldsWhileLiveIsBehind(t *testing.T) {
+	gate := NewPriorityGate(10)
+	gate.ReportLiveLag(11)
+
+	done := waitAsync(context.Background(), gate)
+	expectBlocked(t, done)
+
+	gate.ReportLiveLag(3)
+	expectReleased(t, done)
+}
+
+func TestPriorityGateOpensWhenLiveIsIdle(t *testing.T) {
+	gate := NewPriorityGate(10)
+	gate.ReportLiveLag(50)
+
+	done := waitAsync(context.Background(), gate)
+	expectBlocked(t, done)
+
+	gate.ReportLiveIdle()
+	expectReleased(t, done)
+
+	// Resuming behind the tip closes it again
+	gate.ReportLiveLag(50)
+	expectBlocked(t, waitAsync(context.Background(), gate))
+	gate.ReportLiveIdle()
+}
+
+func TestPriorityGateWaitCancelled(t *testing.T) {
+	gate := NewPriorityGate(0)
+	gate.ReportLiveLag(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := waitAsync(ctx, gate)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Wait = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait ignored the cancelled context")
+	}
+}
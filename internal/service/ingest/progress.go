@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"sync"
+	"time"
+)
+
+// rateWindow is the number of recent ledgers the ingestion rate is averaged over
+const rateWindow = 30
+
+// Progress is a snapshot of where ingestion stands relative to the network
+type Progress struct {
+	LastLedger         uint32    `json:"last_ledger"`           // 0 until the first ledger is processed
+	LastLedgerClosedAt time.Time `json:"last_ledger_closed_at"` // Close time of LastLedger
+	LastProcessedAt    time.Time `json:"last_processed_at"`
+	NetworkLedger      uint32    `json:"network_ledger"` // Latest ledger known to the backend, 0 if unknown
+	Lag                uint32    `json:"lag"`            // Ledgers between LastLedger and NetworkLedger
+	LedgersPerSecond   float64   `json:"ledgers_per_second"`
+	Paused             bool      `json:"paused"`
+}
+
+// progressTracker records processed ledgers for Progress snapshots
+type progressTracker struct {
+	mu       sync.RWMutex
+	progress Progress
+	recent   []time.Time // Processing times of the last rateWindow ledgers
+}
+
+// recordLedger registers a processed ledger
+func (p *progressTracker) recordLedger(sequence uint32, closedAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now().UTC()
+	p.progress.LastLedger = sequence
+	p.progress.LastLedgerClosedAt = closedAt.UTC()
+	p.progress.LastProcessedAt = now
+
+	p.recent = append(p.recent, now)
+	if len(p.recent) > rateWindow {
+		p.recent = p.recent[len(p.recent)-rateWindow:]
+	}
+
+	p.updateLag()
+}
+
+// recordNetworkLedger registers the latest ledger reported by the backend
+func (p *progressTracker) recordNetworkLedger(sequence uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.progress.NetworkLedger = sequence
+	p.updateLag()
+}
+
+// setPaused records whether the live loop is paused
+func (p *progressTracker) setPaused(paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.progress.Paused = paused
+}
+
+// updateLag recomputes the lag, must be called with the lock held
+func (p *progressTracker) updateLag() {
+	p.progress.Lag = 0
+	if p.progress.NetworkLedger > p.progress.LastLedger {
+		p.progress.Lag = p.progress.NetworkLedger - p.progress.LastLedger
+	}
+}
+
+// snapshot returns the current progress with the rate over the recent window
+func (p *progressTracker) snapshot() Progress {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	progress := p.progress
+	if n := len(p.recent); n > 1 {
+		if elapsed := p.recent[n-1].Sub(p.recent[0]).Seconds(); elapsed > 0 {
+			progress.LedgersPerSecond = float64(n-1) / elapsed
+		}
+	}
+
+	return progress
+}
@@ -0,0 +1,155 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"indexer/internal/metrics"
+)
+
+// ErrorClass groups ledger errors that are retried the same way
+type ErrorClass string
+
+const (
+	ErrorClassRateLimited ErrorClass = "rate_limited" // 429 or 503 from the RPC server
+	ErrorClassTimeout     ErrorClass = "timeout"      // Request or dial timeout
+	ErrorClassConnection  ErrorClass = "connection"   // Connection reset, refused or cut short
+	ErrorClassOther       ErrorClass = "other"
+)
+
+// RetryPolicy is how many times, and how far apart, one class of errors is retried
+type RetryPolicy struct {
+	MaxRetries int           // Consecutive failures on a ledger before giving up
+	BaseDelay  time.Duration // Backoff ceiling of the first retry, doubled after each failure
+	MaxDelay   time.Duration // Upper bound of the backoff ceiling
+}
+
+// DefaultRetryPolicies is used for every class not set in Options.RetryPolicies
+var DefaultRetryPolicies = map[ErrorClass]RetryPolicy{
+	ErrorClassRateLimited: {MaxRetries: 10, BaseDelay: 2 * time.Second, MaxDelay: time.Minute},
+	ErrorClassTimeout:     {MaxRetries: 6, BaseDelay: time.Second, MaxDelay: 30 * time.Second},
+	ErrorClassConnection:  {MaxRetries: 6, BaseDelay: 500 * time.Millisecond, MaxDelay: 15 * time.Second},
+	ErrorClassOther:       {MaxRetries: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second},
+}
+
+// ClassifyError maps an error to its retry class from the error types in its chain
+func ClassifyError(err error) ErrorClass {
+	var status interface{ HTTPStatus() int }
+	if errors.As(err, &status) {
+		switch status.HTTPStatus() {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return ErrorClassRateLimited
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorClassTimeout
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return ErrorClassConnection
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorClassConnection
+	}
+
+	return ErrorClassOther
+}
+
+// retryDecision is what to do after a failed attempt
+type retryDecision struct {
+	Class      ErrorClass
+	Attempt    int
+	MaxRetries int
+	Wait       time.Duration
+	GiveUp     bool
+}
+
+// retrier counts consecutive failures on one ledger and computes the backoff with full jitter
+type retrier struct {
+	policies map[ErrorClass]RetryPolicy
+	attempts int
+}
+
+// newRetrier merges overrides into the default policies
+func newRetrier(overrides map[ErrorClass]RetryPolicy) retrier {
+	policies := make(map[ErrorClass]RetryPolicy, len(DefaultRetryPolicies))
+	for class, policy := range DefaultRetryPolicies {
+		policies[class] = policy
+	}
+	for class, policy := range overrides {
+		policies[class] = policy
+	}
+	return retrier{policies: policies}
+}
+
+// fail records a failed attempt, the limit is the one of the latest error's class
+func (r *retrier) fail(err error) retryDecision {
+	r.attempts++
+
+	class := ClassifyError(err)
+	policy, ok := r.policies[class]
+	if !ok {
+		policy = r.policies[ErrorClassOther]
+	}
+
+	decision := retryDecision{Class: class, Attempt: r.attempts, MaxRetries: policy.MaxRetries}
+	if r.attempts >= policy.MaxRetries {
+		decision.GiveUp = true
+		return decision
+	}
+
+	decision.Wait = fullJitter(policy, r.attempts)
+	metrics.LedgerRetries.WithLabelValues(string(class)).Inc()
+
+	// Never retry sooner than the server asked to
+	var hinted interface{ RetryAfter() time.Duration }
+	if errors.As(err, &hinted) && hinted.RetryAfter() > decision.Wait {
+		decision.Wait = hinted.RetryAfter()
+	}
+
+	return decision
+}
+
+// reset clears the failure count after a successful attempt
+func (r *retrier) reset() {
+	r.attempts = 0
+}
+
+// fullJitter returns a random wait between 0 and the exponential ceiling of the attempt
+func fullJitter(policy RetryPolicy, attempt int) time.Duration {
+	ceiling := policy.BaseDelay
+	for i := 1; i < attempt && ceiling < policy.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	if policy.MaxDelay > 0 && ceiling > policy.MaxDelay {
+		ceiling = policy.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// sleep waits for d, returning false if the service is stopped first
+func (s *OrchestratorService) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
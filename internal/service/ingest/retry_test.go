@@ -0,0 +1,110 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// statusError is an RPC error carrying an HTTP status and an optional Retry-After
+type statusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e statusError) Error() string             { return http.StatusText(e.status) }
+func (e statusError) HTTPStatus() int           { return e.status }
+func (e statusError) RetryAfter() time.Duration { return e.retryAfter }
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"429", statusError{status: http.StatusTooManyRequests}, ErrorClassRateLimited},
+		{"503", statusError{status: http.StatusServiceUnavailable}, ErrorClassRateLimited},
+		{"wrapped 429", fmt.Errorf("getLedgers: %w", statusError{status: http.StatusTooManyRequests}), ErrorClassRateLimited},
+		{"500", statusError{status: http.StatusInternalServerError}, ErrorClassOther},
+		{"deadline exceeded", context.DeadlineExceeded, ErrorClassTimeout},
+		{"wrapped deadline exceeded", fmt.Errorf("fetch: %w", context.DeadlineExceeded), ErrorClassTimeout},
+		{"net timeout", &net.OpError{Op: "dial", Err: timeoutError{}}, ErrorClassTimeout},
+		{"canceled", context.Canceled, ErrorClassOther},
+		{"connection reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, ErrorClassConnection},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), ErrorClassConnection},
+		{"unexpected EOF", io.ErrUnexpectedEOF, ErrorClassConnection},
+		{"other net error", &net.OpError{Op: "dial", Err: errors.New("no route to host")}, ErrorClassConnection},
+		{"plain error", errors.New("decode failed"), ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.want {
+				t.Errorf("ClassifyError(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFullJitterBounds(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		ceiling := policy.BaseDelay << (attempt - 1)
+		if ceiling > policy.MaxDelay {
+			ceiling = policy.MaxDelay
+		}
+
+		for range 200 {
+			if wait := fullJitter(policy, attempt); wait < 0 || wait > ceiling {
+				t.Fatalf("attempt %d: wait %s outside [0, %s]", attempt, wait, ceiling)
+			}
+		}
+	}
+
+	if wait := fullJitter(RetryPolicy{}, 3); wait != 0 {
+		t.Errorf("zero policy: wait = %s, want 0", wait)
+	}
+}
+
+func TestRetrierGivesUpPerClass(t *testing.T) {
+	r := newRetrier(map[ErrorClass]RetryPolicy{
+		ErrorClassTimeout: {MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	})
+
+	if decision := r.fail(context.DeadlineExceeded); decision.GiveUp {
+		t.Fatalf("first timeout gave up: %+v", decision)
+	}
+	if decision := r.fail(context.DeadlineExceeded); !decision.GiveUp {
+		t.Fatalf("second timeout did not give up: %+v", decision)
+	}
+
+	r.reset()
+	if decision := r.fail(context.DeadlineExceeded); decision.GiveUp || decision.Attempt != 1 {
+		t.Fatalf("reset did not clear the attempts: %+v", decision)
+	}
+}
+
+func TestRetrierHonorsRetryAfter(t *testing.T) {
+	r := newRetrier(map[ErrorClass]RetryPolicy{
+		ErrorClassRateLimited: {MaxRetries: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	})
+
+	decision := r.fail(statusError{status: http.StatusTooManyRequests, retryAfter: 3 * time.Second})
+	if decision.Class != ErrorClassRateLimited || decision.Wait != 3*time.Second {
+		t.Fatalf("decision = %+v, want rate_limited with a 3s wait", decision)
+	}
+}
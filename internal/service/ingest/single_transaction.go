@@ -0,0 +1,52 @@
+package ingest
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/stellar/go/ingest"
+)
+
+// IngestTransaction fetches the ledger holding a transaction and runs only that transaction through the processors
+func IngestTransaction(ctx context.Context, newBackend BackendFactory, processors []Processor, networkPassphrase string, ledgerSeq uint32, txHash string) error {
+	ledger, err := FetchLedger(ctx, newBackend, ledgerSeq)
+	if err != nil {
+		return fmt.Errorf("error fetching ledger %d: %w", ledgerSeq, err)
+	}
+
+	txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(networkPassphrase, ledger)
+	if err != nil {
+		return fmt.Errorf("error creating transaction reader: %w", err)
+	}
+	defer txReader.Close()
+
+	for {
+		tx, err := txReader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("transaction %s not found in ledger %d", txHash, ledgerSeq)
+			}
+			return fmt.Errorf("error reading transaction: %w", err)
+		}
+
+		if !strings.EqualFold(hex.EncodeToString(tx.Result.TransactionHash[:]), txHash) {
+			continue
+		}
+
+		var errs []error
+		for _, processor := range processors {
+			if err := processor.ProcessTransaction(ctx, tx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", processor.Name(), err))
+			}
+		}
+
+		log.Printf("✅ Transaction %s (ledger %d) processed", txHash, ledgerSeq)
+
+		return errors.Join(errs...)
+	}
+}
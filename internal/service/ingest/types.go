@@ -2,6 +2,9 @@ package ingest
 
 import (
 	"context"
+	"time"
+
+	"indexer/internal/metrics"
 
 	"github.com/stellar/go/ingest"
 	"github.com/stellar/go/xdr"
@@ -14,8 +17,61 @@ type Processor interface {
 	ProcessTransaction(ctx context.Context, tx ingest.LedgerTransaction) error
 }
 
+// Flushable is implemented by processors that accumulate state and must hand it off before shutdown
+type Flushable interface {
+	Flush(ctx context.Context) error
+}
+
 // CheckpointStore defines the interface for managing ledger sequence checkpoints
 type CheckpointStore interface {
 	Save(ctx context.Context, ledgerSeq uint32) error
 	Load(ctx context.Context) (uint32, error)
 }
+
+// LedgerHashCheckpointStore is a CheckpointStore that also keeps the hash of the saved ledger,
+// so a restart can verify that the chain it resumes is the one it left
+type LedgerHashCheckpointStore interface {
+	CheckpointStore
+	SaveWithHash(ctx context.Context, ledgerSeq uint32, hash xdr.Hash) error
+	LoadHash(ctx context.Context) (uint32, xdr.Hash, error) // Hash of the current checkpoint, zero if unknown
+}
+
+// FailedTransaction is a transaction a processor could not handle, kept for a later retry
+type FailedTransaction struct {
+	ID             string     `json:"id"`
+	Processor      string     `json:"processor"`
+	LedgerSequence uint32     `json:"ledger_sequence"`
+	TxIndex        uint32     `json:"tx_index"` // 1-based position in the ledger
+	TxHash         string     `json:"tx_hash"`
+	EnvelopeXDR    string     `json:"envelope_xdr"` // TransactionEnvelope in base64
+	Error          string     `json:"error"`
+	Attempts       int        `json:"attempts"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// FailedTransactionStore is the dead-letter queue for failed transactions
+type FailedTransactionStore interface {
+	SaveFailedTransaction(ctx context.Context, failed FailedTransaction) error
+	ListFailedTransactions(ctx context.Context, unresolvedOnly bool) ([]FailedTransaction, error)
+	UpdateFailedTransaction(ctx context.Context, failed FailedTransaction) error
+}
+
+// NetworkTipFunc returns the last ledger closed by the network
+type NetworkTipFunc func(ctx context.Context) (uint32, error)
+
+// Options holds the settings and optional collaborators of the orchestrator (nil values are disabled)
+type Options struct {
+	NetworkPassphrase  string                     // Passphrase of the network the ledgers come from, required
+	CheckpointStore    CheckpointStore            // Persists progress
+	CheckpointEvery    uint32                     // Save progress every N ledgers (0 = only at the end)
+	CheckpointInterval time.Duration              // Save progress at least this often while ledgers are processed (0 = no time trigger)
+	FailedTransactions FailedTransactionStore     // Receives transactions a processor failed on
+	Freshness          *metrics.FreshnessTracker  // Records the freshness SLO of processed ledgers
+	PriorityGate       *PriorityGate              // Shares capacity between the live and backfill lanes
+	TxTimeout          time.Duration              // Deadline for one processor to handle one transaction (0 = none)
+	Prefetch           int                        // Ledgers fetched ahead of processing (0 = fetch on demand)
+	RetryPolicies      map[ErrorClass]RetryPolicy // Overrides of DefaultRetryPolicies per error class
+	LedgerInfo         LedgerInfoStore            // Receives per-ledger counts and timings
+	NetworkTip         NetworkTipFunc             // Source of the network tip (nil = the backend's latest ledger)
+}
@@ -0,0 +1,119 @@
+package quarantine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"indexer/internal/indexer/types"
+	"indexer/internal/logging"
+	"indexer/internal/metrics"
+)
+
+// Redecoder is implemented by processors able to reprocess a quarantined event
+type Redecoder interface {
+	Name() string
+	Redecode(ctx context.Context, failure types.DecodeFailure) error
+}
+
+// RedecodeResult summarizes a re-decode run
+type RedecodeResult struct {
+	Attempted    int `json:"attempted"`
+	Resolved     int `json:"resolved"`
+	StillFailing int `json:"still_failing"`
+}
+
+// Service records undecodable events and replays them after a decoder fix
+type Service struct {
+	store types.DecodeFailureStore
+
+	mu         sync.RWMutex
+	redecoders map[string]Redecoder
+}
+
+// NewService creates a quarantine service persisting to store
+func NewService(store types.DecodeFailureStore) *Service {
+	return &Service{
+		store:      store,
+		redecoders: make(map[string]Redecoder),
+	}
+}
+
+// Register makes a processor available for re-decoding its quarantined events
+func (s *Service) Register(redecoder Redecoder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.redecoders[redecoder.Name()] = redecoder
+}
+
+// RecordDecodeFailure quarantines an event that could not be decoded
+func (s *Service) RecordDecodeFailure(ctx context.Context, failure types.DecodeFailure) {
+	if failure.ID == "" {
+		failure.ID = newID()
+	}
+	if failure.CreatedAt.IsZero() {
+		failure.CreatedAt = time.Now().UTC()
+	}
+
+	metrics.DecodeFailures.WithLabelValues(failure.Processor).Inc()
+
+	if err := s.store.SaveDecodeFailure(ctx, failure); err != nil {
+		log.Printf("❌ Error quarantining decode failure (ledger %d, tx %s): %v",
+			failure.LedgerSequence, failure.TxHash, err)
+	}
+}
+
+// List returns quarantined failures
+func (s *Service) List(ctx context.Context, unresolvedOnly bool) ([]types.DecodeFailure, error) {
+	return s.store.ListDecodeFailures(ctx, unresolvedOnly)
+}
+
+// RedecodeAll retries every unresolved failure with the processor that produced it
+func (s *Service) RedecodeAll(ctx context.Context) (RedecodeResult, error) {
+	failures, err := s.store.ListDecodeFailures(ctx, true)
+	if err != nil {
+		return RedecodeResult{}, err
+	}
+
+	var result RedecodeResult
+	for _, failure := range failures {
+		s.mu.RLock()
+		redecoder, ok := s.redecoders[failure.Processor]
+		s.mu.RUnlock()
+
+		if !ok {
+			continue
+		}
+
+		result.Attempted++
+
+		if err := redecoder.Redecode(ctx, failure); err != nil {
+			result.StillFailing++
+			continue
+		}
+
+		if err := s.store.MarkDecodeFailureResolved(ctx, failure.ID); err != nil {
+			return result, fmt.Errorf("error resolving decode failure %s: %w", failure.ID, err)
+		}
+
+		metrics.DecodeFailuresResolved.WithLabelValues(failure.Processor).Inc()
+		result.Resolved++
+	}
+
+	logging.Printf(ctx, "♻️  Re-decoded quarantined events: %d attempted, %d resolved, %d still failing",
+		result.Attempted, result.Resolved, result.StillFailing)
+
+	return result, nil
+}
+
+// newID returns a random identifier for a failure
+func newID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
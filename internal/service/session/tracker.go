@@ -0,0 +1,109 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Exit reasons recorded when a session ends
+const (
+	ExitSignal            = "signal"
+	ExitStopped           = "stopped"
+	ExitBackfillCompleted = "backfill_completed"
+	ExitError             = "error"
+	ExitUnclean           = "unclean_shutdown" // The process died without closing its session
+)
+
+// Session is one run of the indexer, from startup to shutdown
+type Session struct {
+	ID              string     `json:"id"`
+	Mode            string     `json:"mode"` // live, backfill or live+backfill
+	StartLedger     uint32     `json:"start_ledger"`
+	EndLedger       uint32     `json:"end_ledger,omitempty"` // Last ledger processed, 0 if none
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds,omitempty"`
+	ExitReason      string     `json:"exit_reason,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// Store persists processing sessions
+type Store interface {
+	SaveSession(ctx context.Context, session Session) error
+	ListSessions(ctx context.Context) ([]Session, error)
+}
+
+// Tracker records the session of the running process
+type Tracker struct {
+	store   Store
+	current *Session
+}
+
+// NewTracker creates a session tracker persisting to store
+func NewTracker(store Store) *Tracker {
+	return &Tracker{store: store}
+}
+
+// Begin opens the session of this process. Sessions left open by a previous
+// process are closed first as unclean shutdowns.
+func (t *Tracker) Begin(ctx context.Context, mode string, startLedger uint32) error {
+	sessions, err := t.store.ListSessions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, previous := range sessions {
+		if previous.EndedAt != nil || previous.ExitReason != "" {
+			continue
+		}
+
+		log.Printf("⚠️  Session %s started at %s was not closed, recording an unclean shutdown", previous.ID, previous.StartedAt.Format(time.RFC3339))
+		previous.ExitReason = ExitUnclean
+		if err := t.store.SaveSession(ctx, previous); err != nil {
+			return err
+		}
+	}
+
+	t.current = &Session{
+		ID:          newID(),
+		Mode:        mode,
+		StartLedger: startLedger,
+		StartedAt:   time.Now().UTC(),
+	}
+
+	return t.store.SaveSession(ctx, *t.current)
+}
+
+// End closes the current session with the last processed ledger and the reason for exiting
+func (t *Tracker) End(ctx context.Context, endLedger uint32, reason string, exitErr error) error {
+	if t.current == nil {
+		return fmt.Errorf("no session in progress")
+	}
+
+	endedAt := time.Now().UTC()
+	t.current.EndLedger = endLedger
+	t.current.EndedAt = &endedAt
+	t.current.DurationSeconds = endedAt.Sub(t.current.StartedAt).Seconds()
+	t.current.ExitReason = reason
+	if exitErr != nil {
+		t.current.Error = exitErr.Error()
+	}
+
+	return t.store.SaveSession(ctx, *t.current)
+}
+
+// List returns every recorded session, oldest first
+func (t *Tracker) List(ctx context.Context) ([]Session, error) {
+	return t.store.ListSessions(ctx)
+}
+
+// newID returns a random session identifier
+func newID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"indexer/internal/service/backfill"
+)
+
+// FileBackfillStore persists backfill jobs as JSON files in a directory
+type FileBackfillStore struct {
+	dir string
+}
+
+// NewFileBackfillStore creates a backfill job store rooted at dir
+func NewFileBackfillStore(dir string) *FileBackfillStore {
+	return &FileBackfillStore{dir: dir}
+}
+
+// SaveJob writes the job atomically (temp file + rename)
+func (f *FileBackfillStore) SaveJob(ctx context.Context, job backfill.Job) error {
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("error creating backfill directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding backfill job: %w", err)
+	}
+
+	path := f.jobPath(job.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing backfill job: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error replacing backfill job: %w", err)
+	}
+
+	return nil
+}
+
+// LoadJob reads a job by id, returning nil if it has never been saved
+func (f *FileBackfillStore) LoadJob(ctx context.Context, id string) (*backfill.Job, error) {
+	data, err := os.ReadFile(f.jobPath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading backfill job: %w", err)
+	}
+
+	var job backfill.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid backfill job %s: %w", id, err)
+	}
+
+	return &job, nil
+}
+
+// jobPath returns the file holding the given job
+func (f *FileBackfillStore) jobPath(id string) string {
+	return filepath.Join(f.dir, "backfill_"+id+".json")
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"indexer/internal/service/ingest"
+
+	"github.com/stellar/go/xdr"
+)
+
+// maxCheckpointHistory bounds the records kept, oldest first out
+const maxCheckpointHistory = 1000
+
+// CheckpointRecord is one saved checkpoint with the downstream row counts at that point
+type CheckpointRecord struct {
+	Ledger  uint32         `json:"ledger"`
+	Hash    string         `json:"hash,omitempty"` // Hex hash of the ledger, when known
+	SavedAt time.Time      `json:"saved_at"`
+	Rows    map[string]int `json:"rows,omitempty"`
+}
+
+// RowCounter is a downstream store counted in every checkpoint record
+type RowCounter interface {
+	CountRows(ctx context.Context) (int, error)
+}
+
+// LedgerTruncater is a downstream store whose rows can be deleted past a ledger
+type LedgerTruncater interface {
+	TruncateAfter(ctx context.Context, ledger uint32) (int, error)
+}
+
+// FileCheckpointHistory saves checkpoints to an inner store and keeps a history of them in a JSON file
+type FileCheckpointHistory struct {
+	store    ingest.CheckpointStore
+	path     string
+	counters map[string]RowCounter
+	mu       sync.Mutex
+}
+
+// NewFileCheckpointHistory wraps store, recording each save in the file at path with the rows of counters
+func NewFileCheckpointHistory(store ingest.CheckpointStore, path string, counters map[string]RowCounter) *FileCheckpointHistory {
+	return &FileCheckpointHistory{store: store, path: path, counters: counters}
+}
+
+// Save saves the checkpoint, then appends it to the history
+func (f *FileCheckpointHistory) Save(ctx context.Context, ledgerSeq uint32) error {
+	return f.SaveWithHash(ctx, ledgerSeq, xdr.Hash{})
+}
+
+// SaveWithHash saves the checkpoint, then appends it to the history with the ledger hash (omitted when zero)
+func (f *FileCheckpointHistory) SaveWithHash(ctx context.Context, ledgerSeq uint32, hash xdr.Hash) error {
+	if err := f.store.Save(ctx, ledgerSeq); err != nil {
+		return err
+	}
+
+	record := CheckpointRecord{Ledger: ledgerSeq, SavedAt: time.Now().UTC(), Rows: make(map[string]int, len(f.counters))}
+	if hash != (xdr.Hash{}) {
+		record.Hash = hash.HexString()
+	}
+	for name, counter := range f.counters {
+		rows, err := counter.CountRows(ctx)
+		if err != nil {
+			return fmt.Errorf("error counting %s rows: %w", name, err)
+		}
+		record.Rows[name] = rows
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	records = append(records, record)
+	if len(records) > maxCheckpointHistory {
+		records = records[len(records)-maxCheckpointHistory:]
+	}
+
+	return f.write(records)
+}
+
+// Load returns the current checkpoint from the inner store
+func (f *FileCheckpointHistory) Load(ctx context.Context) (uint32, error) {
+	return f.store.Load(ctx)
+}
+
+// LoadHash returns the current checkpoint and the hash recorded with it, zero when the latest record has none
+func (f *FileCheckpointHistory) LoadHash(ctx context.Context) (uint32, xdr.Hash, error) {
+	ledgerSeq, err := f.store.Load(ctx)
+	if err != nil || ledgerSeq == 0 {
+		return ledgerSeq, xdr.Hash{}, err
+	}
+
+	f.mu.Lock()
+	records, err := f.load()
+	f.mu.Unlock()
+	if err != nil {
+		return 0, xdr.Hash{}, err
+	}
+
+	// Only the latest record describes the current checkpoint
+	if len(records) == 0 || records[len(records)-1].Ledger != ledgerSeq || records[len(records)-1].Hash == "" {
+		return ledgerSeq, xdr.Hash{}, nil
+	}
+
+	var hash xdr.Hash
+	decoded, err := hex.DecodeString(records[len(records)-1].Hash)
+	if err != nil || len(decoded) != len(hash) {
+		return 0, xdr.Hash{}, fmt.Errorf("invalid hash in checkpoint history file %s", f.path)
+	}
+	copy(hash[:], decoded)
+
+	return ledgerSeq, hash, nil
+}
+
+// List returns the recorded checkpoints, oldest first
+func (f *FileCheckpointHistory) List(ctx context.Context) ([]CheckpointRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.load()
+}
+
+// TruncateAfter drops the records past ledger
+func (f *FileCheckpointHistory) TruncateAfter(ctx context.Context, ledger uint32) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := records[:0]
+	for _, record := range records {
+		if record.Ledger <= ledger {
+			kept = append(kept, record)
+		}
+	}
+
+	removed := len(records) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, f.write(kept)
+}
+
+// load reads every record from disk
+func (f *FileCheckpointHistory) load() ([]CheckpointRecord, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading checkpoint history: %w", err)
+	}
+
+	var records []CheckpointRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint history file %s: %w", f.path, err)
+	}
+
+	return records, nil
+}
+
+// write replaces the file atomically (temp file + rename)
+func (f *FileCheckpointHistory) write(records []CheckpointRecord) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating checkpoint history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint history: %w", err)
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing checkpoint history: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error replacing checkpoint history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileCheckpointStore persists the last processed ledger sequence in a plain text file
+type FileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore creates a checkpoint store backed by the file at path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Save writes the ledger sequence atomically (temp file + rename)
+func (f *FileCheckpointStore) Save(ctx context.Context, ledgerSeq uint32) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating checkpoint directory: %w", err)
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatUint(uint64(ledgerSeq), 10)), 0o644); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error replacing checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the saved ledger sequence, or 0 if no checkpoint has been written yet
+func (f *FileCheckpointStore) Load(ctx context.Context) (uint32, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+
+	ledgerSeq, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint in %s: %w", f.path, err)
+	}
+
+	return uint32(ledgerSeq), nil
+}
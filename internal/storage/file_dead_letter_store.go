@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"indexer/internal/notify"
+)
+
+// FileDeadLetterStore appends undeliverable webhook notifications to a JSON lines file
+type FileDeadLetterStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileDeadLetterStore creates a dead letter store writing to the file at path
+func NewFileDeadLetterStore(path string) *FileDeadLetterStore {
+	return &FileDeadLetterStore{path: path}
+}
+
+// SaveDeadLetter appends the dead letter as a single JSON line
+func (f *FileDeadLetterStore) SaveDeadLetter(ctx context.Context, deadLetter notify.DeadLetter) error {
+	line, err := json.Marshal(deadLetter)
+	if err != nil {
+		return fmt.Errorf("error encoding dead letter: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating dead letter directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening dead letter file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing dead letter: %w", err)
+	}
+
+	return nil
+}
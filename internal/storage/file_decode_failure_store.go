@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"indexer/internal/indexer/types"
+)
+
+// FileDecodeFailureStore keeps quarantined decode failures in a JSON file
+type FileDecodeFailureStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileDecodeFailureStore creates a decode failure store backed by the file at path
+func NewFileDecodeFailureStore(path string) *FileDecodeFailureStore {
+	return &FileDecodeFailureStore{path: path}
+}
+
+// SaveDecodeFailure adds a failure to the quarantine
+func (f *FileDecodeFailureStore) SaveDecodeFailure(ctx context.Context, failure types.DecodeFailure) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failures, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	return f.write(append(failures, failure))
+}
+
+// ListDecodeFailures returns the quarantined failures, optionally only those not yet resolved
+func (f *FileDecodeFailureStore) ListDecodeFailures(ctx context.Context, unresolvedOnly bool) ([]types.DecodeFailure, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failures, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if !unresolvedOnly {
+		return failures, nil
+	}
+
+	unresolved := make([]types.DecodeFailure, 0, len(failures))
+	for _, failure := range failures {
+		if failure.ResolvedAt == nil {
+			unresolved = append(unresolved, failure)
+		}
+	}
+
+	return unresolved, nil
+}
+
+// MarkDecodeFailureResolved flags a failure as successfully re-decoded
+func (f *FileDecodeFailureStore) MarkDecodeFailureResolved(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failures, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range failures {
+		if failures[i].ID == id {
+			failures[i].ResolvedAt = &now
+			return f.write(failures)
+		}
+	}
+
+	return fmt.Errorf("decode failure %s not found", id)
+}
+
+// CountRows returns the number of quarantined failures
+func (f *FileDecodeFailureStore) CountRows(ctx context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failures, err := f.load()
+	return len(failures), err
+}
+
+// TruncateAfter deletes the failures of ledgers past ledger
+func (f *FileDecodeFailureStore) TruncateAfter(ctx context.Context, ledger uint32) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failures, err := f.load()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := failures[:0]
+	for _, failure := range failures {
+		if failure.LedgerSequence <= ledger {
+			kept = append(kept, failure)
+		}
+	}
+
+	removed := len(failures) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, f.write(kept)
+}
+
+// load reads every failure from disk
+func (f *FileDecodeFailureStore) load() ([]types.DecodeFailure, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading decode failures: %w", err)
+	}
+
+	var failures []types.DecodeFailure
+	if err := json.Unmarshal(data, &failures); err != nil {
+		return nil, fmt.Errorf("invalid decode failures file %s: %w", f.path, err)
+	}
+
+	return failures, nil
+}
+
+// write replaces the file atomically (temp file + rename)
+func (f *FileDecodeFailureStore) write(failures []types.DecodeFailure) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating decode failures directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding decode failures: %w", err)
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing decode failures: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error replacing decode failures: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"indexer/internal/service/ingest"
+)
+
+// FileFailedTransactionStore keeps the failed transaction dead-letter queue in a JSON file
+type FileFailedTransactionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileFailedTransactionStore creates a failed transaction store backed by the file at path
+func NewFileFailedTransactionStore(path string) *FileFailedTransactionStore {
+	return &FileFailedTransactionStore{path: path}
+}
+
+// SaveFailedTransaction adds a transaction to the queue
+func (f *FileFailedTransactionStore) SaveFailedTransaction(ctx context.Context, failed ingest.FailedTransaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	return f.write(append(entries, failed))
+}
+
+// ListFailedTransactions returns the queued transactions, optionally only those not yet resolved
+func (f *FileFailedTransactionStore) ListFailedTransactions(ctx context.Context, unresolvedOnly bool) ([]ingest.FailedTransaction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if !unresolvedOnly {
+		return entries, nil
+	}
+
+	unresolved := make([]ingest.FailedTransaction, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ResolvedAt == nil {
+			unresolved = append(unresolved, entry)
+		}
+	}
+
+	return unresolved, nil
+}
+
+// UpdateFailedTransaction replaces the stored entry with the same ID
+func (f *FileFailedTransactionStore) UpdateFailedTransaction(ctx context.Context, failed ingest.FailedTransaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if entries[i].ID == failed.ID {
+			entries[i] = failed
+			return f.write(entries)
+		}
+	}
+
+	return fmt.Errorf("failed transaction %s not found", failed.ID)
+}
+
+// CountRows returns the number of queued transactions
+func (f *FileFailedTransactionStore) CountRows(ctx context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	return len(entries), err
+}
+
+// TruncateAfter deletes the transactions of ledgers past ledger
+func (f *FileFailedTransactionStore) TruncateAfter(ctx context.Context, ledger uint32) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.LedgerSequence <= ledger {
+			kept = append(kept, entry)
+		}
+	}
+
+	removed := len(entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	return removed, f.write(kept)
+}
+
+// load reads every entry from disk
+func (f *FileFailedTransactionStore) load() ([]ingest.FailedTransaction, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading failed transactions: %w", err)
+	}
+
+	var entries []ingest.FailedTransaction
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid failed transactions file %s: %w", f.path, err)
+	}
+
+	return entries, nil
+}
+
+// write replaces the file atomically (temp file + rename)
+func (f *FileFailedTransactionStore) write(entries []ingest.FailedTransaction) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating failed transactions directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding failed transactions: %w", err)
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing failed transactions: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error replacing failed transactions: %w", err)
+	}
+
+	return nil
+}
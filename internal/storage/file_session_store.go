@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"indexer/internal/service/session"
+)
+
+// FileSessionStore keeps the processing session history in a JSON file
+type FileSessionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSessionStore creates a session store backed by the file at path
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+// SaveSession adds a session or replaces the stored one with the same ID
+func (f *FileSessionStore) SaveSession(ctx context.Context, s session.Session) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sessions, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		if sessions[i].ID == s.ID {
+			sessions[i] = s
+			return f.write(sessions)
+		}
+	}
+
+	return f.write(append(sessions, s))
+}
+
+// ListSessions returns every recorded session, oldest first
+func (f *FileSessionStore) ListSessions(ctx context.Context) ([]session.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.load()
+}
+
+// load reads every session from disk
+func (f *FileSessionStore) load() ([]session.Session, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading sessions: %w", err)
+	}
+
+	var sessions []session.Session
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("invalid sessions file %s: %w", f.path, err)
+	}
+
+	return sessions, nil
+}
+
+// write replaces the file atomically (temp file + rename)
+func (f *FileSessionStore) write(sessions []session.Session) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("error creating sessions directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding sessions: %w", err)
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing sessions: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("error replacing sessions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"indexer/internal/service/ingest"
+)
+
+// MemoryLedgerInfoStore keeps the LedgerInfo of the most recent ledgers in a ring buffer
+type MemoryLedgerInfoStore struct {
+	mu      sync.RWMutex
+	entries []ingest.LedgerInfo
+	next    int // Slot overwritten by the next save once the buffer is full
+}
+
+// NewMemoryLedgerInfoStore creates a store holding up to capacity ledgers
+func NewMemoryLedgerInfoStore(capacity int) *MemoryLedgerInfoStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryLedgerInfoStore{entries: make([]ingest.LedgerInfo, 0, capacity)}
+}
+
+// SaveLedgerInfo records a processed ledger, dropping the oldest one when full
+func (m *MemoryLedgerInfoStore) SaveLedgerInfo(ctx context.Context, info ingest.LedgerInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.entries) < cap(m.entries) {
+		m.entries = append(m.entries, info)
+		return nil
+	}
+
+	m.entries[m.next] = info
+	m.next = (m.next + 1) % len(m.entries)
+	return nil
+}
+
+// Range returns the stored ledgers between from and to inclusive, ordered by sequence.
+// A ledger processed twice (after a seek) is reported with its latest run.
+func (m *MemoryLedgerInfoStore) Range(from, to uint32) []ingest.LedgerInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bySequence := make(map[uint32]ingest.LedgerInfo)
+	for i := range m.entries {
+		// Oldest first, so later runs overwrite earlier ones
+		info := m.entries[(m.next+i)%len(m.entries)]
+		if info.Sequence >= from && info.Sequence <= to {
+			bySequence[info.Sequence] = info
+		}
+	}
+
+	ledgers := make([]ingest.LedgerInfo, 0, len(bySequence))
+	for _, info := range bySequence {
+		ledgers = append(ledgers, info)
+	}
+	sort.Slice(ledgers, func(i, j int) bool { return ledgers[i].Sequence < ledgers[j].Sequence })
+
+	return ledgers
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// logExporter writes sampled spans slower than threshold to the log
+type logExporter struct {
+	threshold time.Duration
+}
+
+// ExportSpans logs every slow span with its attributes
+func (e *logExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		duration := span.EndTime().Sub(span.StartTime())
+		if duration < e.threshold {
+			continue
+		}
+
+		attrs := make([]string, 0, len(span.Attributes()))
+		for _, attr := range span.Attributes() {
+			attrs = append(attrs, fmt.Sprintf("%s=%s", attr.Key, attr.Value.Emit()))
+		}
+
+		log.Printf("🐢 Slow span %s took %s (trace %s) %s",
+			span.Name(), duration, span.SpanContext().TraceID(), strings.Join(attrs, " "))
+	}
+
+	return nil
+}
+
+// Shutdown has nothing to release
+func (e *logExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
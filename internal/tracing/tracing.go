@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Config holds the tracing settings
+type Config struct {
+	Enabled       bool          // Install a tracer provider (spans are no-ops otherwise)
+	SampleRatio   float64       // Fraction of ledgers traced
+	SlowThreshold time.Duration // Sampled spans slower than this are logged
+}
+
+// Setup installs the global tracer provider and returns a function flushing pending spans on shutdown.
+// When tracing is disabled the OpenTelemetry no-op provider stays in place.
+func Setup(config Config) func(context.Context) error {
+	if !config.Enabled {
+		return func(context.Context) error { return nil }
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithBatcher(&logExporter{threshold: config.SlowThreshold}),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	log.Printf("🔭 Tracing enabled (sample ratio %.2f, slow span threshold %s)", config.SampleRatio, config.SlowThreshold)
+
+	return provider.Shutdown
+}
@@ -0,0 +1,155 @@
+// Package tsgen generates TypeScript interfaces from Go API models.
+package tsgen
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generator collects Go struct types and renders them as TypeScript interfaces
+type Generator struct {
+	order []reflect.Type
+	seen  map[reflect.Type]bool
+}
+
+// NewGenerator creates an empty generator
+func NewGenerator() *Generator {
+	return &Generator{seen: make(map[reflect.Type]bool)}
+}
+
+// Add registers the type of each model (and every struct type it references)
+func (g *Generator) Add(models ...any) {
+	for _, model := range models {
+		g.addType(reflect.TypeOf(model))
+	}
+}
+
+// addType registers a struct type and walks its fields
+func (g *Generator) addType(t reflect.Type) {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct || t == timeType || g.seen[t] {
+		return
+	}
+	g.seen[t] = true
+
+	for _, field := range jsonFields(t) {
+		g.addType(elemType(field.Type))
+	}
+
+	g.order = append(g.order, t)
+}
+
+// Write renders every registered type as an exported TypeScript interface
+func (g *Generator) Write(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("// Code generated by indexer gen types. DO NOT EDIT.\n")
+
+	for _, t := range g.order {
+		fmt.Fprintf(&b, "\nexport interface %s {\n", t.Name())
+		for _, field := range jsonFields(t) {
+			optional := ""
+			if field.optional {
+				optional = "?"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", field.name, optional, tsType(field.Type))
+		}
+		b.WriteString("}\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// jsonField is a struct field as it appears in the JSON encoding
+type jsonField struct {
+	reflect.StructField
+	name     string
+	optional bool
+}
+
+// jsonFields returns the JSON-visible fields of t, flattening embedded structs like encoding/json
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(indirect(field.Type))...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{
+			StructField: field,
+			name:        name,
+			optional:    strings.Contains(opts, "omitempty") || field.Type.Kind() == reflect.Pointer,
+		})
+	}
+
+	return fields
+}
+
+// tsType maps a Go type to its TypeScript equivalent
+func tsType(t reflect.Type) string {
+	t = indirect(t)
+
+	if t == timeType {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return tsType(t.Elem()) + "[]"
+	case reflect.Map:
+		return fmt.Sprintf("Record<string, %s>", tsType(t.Elem()))
+	case reflect.Struct:
+		return t.Name()
+	default:
+		return "unknown"
+	}
+}
+
+// elemType unwraps pointers, slices and maps down to the element type
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// indirect unwraps pointer types
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
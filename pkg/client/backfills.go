@@ -0,0 +1,16 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// GetBackfill returns the progress of the backfill covering [start, end]
+func (c *Client) GetBackfill(ctx context.Context, start, end uint32) (*Backfill, error) {
+	var backfill Backfill
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/admin/backfills/%d_%d", start, end), nil, &backfill); err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
@@ -0,0 +1,133 @@
+// Package client is a Go client for the indexer HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config holds the client settings
+type Config struct {
+	BaseURL    string        // API base URL, e.g. http://localhost:8080
+	HTTPClient *http.Client  // Optional HTTP client (defaults to a 30s timeout client)
+	Retries    int           // Retries of idempotent requests on network errors, 429 and 5xx responses
+	RetryWait  time.Duration // Wait before the first retry, doubled after each attempt
+}
+
+// Client calls the indexer API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retries    int
+	retryWait  time.Duration
+}
+
+// New creates a client for the API at config.BaseURL
+func New(config Config) *Client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	retryWait := config.RetryWait
+	if retryWait <= 0 {
+		retryWait = 500 * time.Millisecond
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		httpClient: httpClient,
+		retries:    config.Retries,
+		retryWait:  retryWait,
+	}
+}
+
+// do sends a request and decodes the JSON response into out (if non-nil), retrying transient failures
+// of idempotent requests
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	retries := c.retries
+	if method != http.MethodGet && method != http.MethodDelete {
+		retries = 0
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+	}
+
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		err := c.doOnce(ctx, method, path, payload, out)
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// doOnce performs a single request
+func (c *Client) doOnce(ctx context.Context, method, path string, payload []byte, out any) error {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// retryable reports whether a failed request may succeed if repeated
+func retryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	// Context errors are final, anything else is a transport error
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,51 @@
+package client
+
+import "time"
+
+// Webhook is a registered webhook subscription
+type Webhook struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Signed     bool      `json:"signed"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateWebhookRequest registers a webhook; an empty EventTypes subscribes to every event
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+// BackfillChunk is the status of one chunk of a backfill
+type BackfillChunk struct {
+	Start     uint32    `json:"start"`
+	End       uint32    `json:"end"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Backfill is the progress of a bounded backfill
+type Backfill struct {
+	ID              string          `json:"id"`
+	Start           uint32          `json:"start"`
+	End             uint32          `json:"end"`
+	ChunkSize       uint32          `json:"chunk_size"`
+	Chunks          []BackfillChunk `json:"chunks"`
+	CreatedAt       time.Time       `json:"created_at"`
+	CompletedChunks int             `json:"completed_chunks"`
+	TotalChunks     int             `json:"total_chunks"`
+	PercentComplete float64         `json:"percent_complete"`
+}
+
+// APIError is returned when the API answers with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Message    string `json:"error"`
+}
+
+func (e *APIError) Error() string {
+	return "indexer api: " + e.Message
+}
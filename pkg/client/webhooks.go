@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListWebhooks returns every registered webhook subscription
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+	if err := c.do(ctx, http.MethodGet, "/webhooks", nil, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook registers a webhook subscription
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (*Webhook, error) {
+	var webhook Webhook
+	if err := c.do(ctx, http.MethodPost, "/webhooks", req, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook removes a webhook subscription
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/webhooks/"+url.PathEscape(id), nil, nil)
+}
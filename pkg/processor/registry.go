@@ -0,0 +1,88 @@
+// Package processor is the extension point for custom ledger processors.
+//
+// A processor is compiled into the indexer by importing its package for side effects, with an
+// init function that calls Register, or built as a Go plugin (go build -buildmode=plugin) whose
+// init does the same and that is listed with its path in the indexer config.
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// Processor receives every ledger and, unless filtered by contract, every transaction
+type Processor interface {
+	Name() string
+	ProcessLedger(ctx context.Context, ledger xdr.LedgerCloseMeta) error
+	ProcessTransaction(ctx context.Context, tx ingest.LedgerTransaction) error
+}
+
+// Settings configure one instance of a registered processor
+type Settings struct {
+	Contracts []string          // Contract IDs or ID prefixes whose transactions are handled (empty = all)
+	Options   map[string]string // Processor specific options from the config file
+}
+
+// Factory builds a processor from its settings. It is called once per ingestion lane (live, each
+// backfill chunk, failed transaction retries), and the indexer never calls one instance from two
+// goroutines at once, so instance fields need no locking. Anything the instances share, such as
+// a database handle or package-level state, must be safe for concurrent use.
+type Factory func(settings Settings) (Processor, error)
+
+// Registration is a processor available to the indexer
+type Registration struct {
+	Name    string
+	Order   int // Processors run in ascending order, after the built-in ones
+	Factory Factory
+}
+
+var (
+	mu       sync.Mutex
+	registry = make(map[string]Registration)
+)
+
+// Register makes a processor available under name. It panics on a duplicate name, like
+// database/sql drivers, since that is a build mistake.
+func Register(name string, order int, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("processor %q registered twice", name))
+	}
+	registry[name] = Registration{Name: name, Order: order, Factory: factory}
+}
+
+// Lookup returns the registration of a processor
+func Lookup(name string) (Registration, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registration, ok := registry[name]
+	return registration, ok
+}
+
+// Registered returns every registered processor, by order then name
+func Registered() []Registration {
+	mu.Lock()
+	defer mu.Unlock()
+
+	list := make([]Registration, 0, len(registry))
+	for _, registration := range registry {
+		list = append(list, registration)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Order != list[j].Order {
+			return list[i].Order < list[j].Order
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}